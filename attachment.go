@@ -0,0 +1,102 @@
+package redmine
+
+import (
+	"context"
+	"io"
+	"strconv"
+)
+
+type attachmentResult struct {
+	Attachment Attachment `json:"attachment"`
+}
+
+type attachmentRequest struct {
+	Attachment attachmentUpdate `json:"attachment"`
+}
+
+type attachmentUpdate struct {
+	Filename    string `json:"filename,omitempty"`
+	Description string `json:"description"`
+}
+
+// Attachment contains the metadata of a file attached to an issue, a wiki page or another container.
+//
+// See also: https://www.redmine.org/projects/redmine/wiki/Rest_Attachments
+type Attachment struct {
+	Id          int    `json:"id"`
+	Filename    string `json:"filename"`
+	Filesize    int64  `json:"filesize"`
+	ContentType string `json:"content_type"`
+	Description string `json:"description"`
+	// ContentURL contains the absolute URL to download the file, see DownloadAttachment.
+	ContentURL string `json:"content_url"`
+	// ThumbnailURL contains the absolute URL of a thumbnail of images, see DownloadThumbnail.
+	ThumbnailURL string   `json:"thumbnail_url,omitempty"`
+	Author       *IdName  `json:"author"`
+	CreatedOn    DateTime `json:"created_on"`
+}
+
+// Attachment returns the metadata of the attachment with the given id.
+func (c *Client) Attachment(id int) (*Attachment, error) {
+	return c.AttachmentContext(context.Background(), id)
+}
+
+// AttachmentContext is like Attachment but binds the request to ctx.
+func (c *Client) AttachmentContext(ctx context.Context, id int) (*Attachment, error) {
+	var r attachmentResult
+	err := c.get(ctx, "/attachments/"+strconv.Itoa(id)+".json", &r)
+	if err != nil {
+		return nil, err
+	}
+	return &r.Attachment, nil
+}
+
+// DownloadAttachment streams the content of the attachment with the given id to w. It requests the same resource as
+// Attachment.ContentURL but relative to the endpoint of the client, so that the credentials of the client are sent to
+// the configured Redmine only.
+func (c *Client) DownloadAttachment(id int, w io.Writer) error {
+	return c.DownloadAttachmentContext(context.Background(), id, w)
+}
+
+// DownloadAttachmentContext is like DownloadAttachment but binds the request to ctx.
+func (c *Client) DownloadAttachmentContext(ctx context.Context, id int, w io.Writer) error {
+	return c.get(ctx, "/attachments/download/"+strconv.Itoa(id), w)
+}
+
+// DownloadThumbnail streams the thumbnail of the image attachment with the given id to w. size sets the size of the
+// thumbnail in pixels; values below 1 use the size configured in Redmine.
+func (c *Client) DownloadThumbnail(id int, size int, w io.Writer) error {
+	return c.DownloadThumbnailContext(context.Background(), id, size, w)
+}
+
+// DownloadThumbnailContext is like DownloadThumbnail but binds the request to ctx.
+func (c *Client) DownloadThumbnailContext(ctx context.Context, id int, size int, w io.Writer) error {
+	path := "/attachments/thumbnail/" + strconv.Itoa(id)
+	if size > 0 {
+		path += "/" + strconv.Itoa(size)
+	}
+	return c.get(ctx, path, w)
+}
+
+// UpdateAttachment changes filename and description of the attachment with the id attachment.Id. An empty Filename
+// keeps the current filename.
+func (c *Client) UpdateAttachment(attachment Attachment) error {
+	return c.UpdateAttachmentContext(context.Background(), attachment)
+}
+
+// UpdateAttachmentContext is like UpdateAttachment but binds the request to ctx.
+func (c *Client) UpdateAttachmentContext(ctx context.Context, attachment Attachment) error {
+	return c.patch(ctx, "/attachments/"+strconv.Itoa(attachment.Id)+".json", attachmentRequest{
+		Attachment: attachmentUpdate{Filename: attachment.Filename, Description: attachment.Description},
+	}, nil)
+}
+
+// DeleteAttachment deletes the attachment with the given id.
+func (c *Client) DeleteAttachment(id int) error {
+	return c.DeleteAttachmentContext(context.Background(), id)
+}
+
+// DeleteAttachmentContext is like DeleteAttachment but binds the request to ctx.
+func (c *Client) DeleteAttachmentContext(ctx context.Context, id int) error {
+	return c.delete(ctx, "/attachments/"+strconv.Itoa(id)+".json")
+}
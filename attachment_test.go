@@ -0,0 +1,131 @@
+package redmine
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_Attachment(t *testing.T) {
+	t.Run("should parse attachment metadata", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/attachments/6.json", r.URL.Path)
+			_, _ = fmt.Fprintln(w, `{
+  "attachment": {
+    "id": 6,
+    "filename": "screenshot.png",
+    "filesize": 8542,
+    "content_type": "image/png",
+    "description": "Login page",
+    "content_url": "https://redmine.example.com/attachments/download/6/screenshot.png",
+    "thumbnail_url": "https://redmine.example.com/attachments/thumbnail/6",
+    "author": {"id": 1, "name": "Redmine Admin"},
+    "created_on": "2021-03-01T10:00:00Z"
+  }
+}`)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		actual, err := sut.Attachment(6)
+
+		require.NoError(t, err)
+		assert.Equal(t, "screenshot.png", actual.Filename)
+		assert.Equal(t, int64(8542), actual.Filesize)
+		assert.Equal(t, "image/png", actual.ContentType)
+		assert.Equal(t, "https://redmine.example.com/attachments/download/6/screenshot.png", actual.ContentURL)
+		assert.Equal(t, &IdName{Id: 1, Name: "Redmine Admin"}, actual.Author)
+		assert.Equal(t, "2021-03-01T10:00:00Z", actual.CreatedOn.String())
+	})
+}
+
+func TestClient_DownloadAttachment(t *testing.T) {
+	t.Run("should stream the content with credentials", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/attachments/download/6", r.URL.Path)
+			assert.Equal(t, "apiKey", r.Header.Get("X-Redmine-API-Key"))
+			w.Header().Set("Content-Type", "image/png")
+			_, _ = w.Write([]byte{0x89, 'P', 'N', 'G'})
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+		var buf bytes.Buffer
+
+		err := sut.DownloadAttachment(6, &buf)
+
+		require.NoError(t, err)
+		assert.Equal(t, []byte{0x89, 'P', 'N', 'G'}, buf.Bytes())
+	})
+
+	t.Run("should not write error responses", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = fmt.Fprintln(w, "not found")
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+		var buf bytes.Buffer
+
+		err := sut.DownloadAttachment(6, &buf)
+
+		assert.True(t, errors.Is(err, ErrNotFound))
+		assert.Empty(t, buf.Bytes())
+	})
+}
+
+func TestClient_DownloadThumbnail(t *testing.T) {
+	t.Run("should request the thumbnail in the given size", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/attachments/thumbnail/6/200", r.URL.Path)
+			_, _ = w.Write([]byte("thumbnail"))
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+		var buf bytes.Buffer
+
+		err := sut.DownloadThumbnail(6, 200, &buf)
+
+		require.NoError(t, err)
+		assert.Equal(t, "thumbnail", buf.String())
+	})
+}
+
+func TestClient_UpdateAttachment(t *testing.T) {
+	t.Run("should patch filename and description only", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPatch, r.Method)
+			assert.Equal(t, "/attachments/6.json", r.URL.Path)
+			body, _ := ioutil.ReadAll(r.Body)
+			assert.JSONEq(t, `{"attachment": {"filename": "login.png", "description": "Login page"}}`, string(body))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		err := sut.UpdateAttachment(Attachment{Id: 6, Filename: "login.png", Description: "Login page", Filesize: 8542})
+
+		require.NoError(t, err)
+	})
+}
+
+func TestClient_DeleteAttachment(t *testing.T) {
+	t.Run("should delete the attachment", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodDelete, r.Method)
+			assert.Equal(t, "/attachments/6.json", r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		err := sut.DeleteAttachment(6)
+
+		require.NoError(t, err)
+	})
+}
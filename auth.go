@@ -0,0 +1,66 @@
+package redmine
+
+import (
+	"context"
+	"net/http"
+)
+
+// Authenticator adds the credentials of a client to every request before it is sent to Redmine.
+type Authenticator interface {
+	Authenticate(req *http.Request)
+}
+
+// AuthenticatorFunc adapts an ordinary function to the Authenticator interface.
+type AuthenticatorFunc func(req *http.Request)
+
+// Authenticate calls f(req).
+func (f AuthenticatorFunc) Authenticate(req *http.Request) {
+	f(req)
+}
+
+// APIKeyAuth authenticates requests with the API key of a Redmine user which is sent in the X-Redmine-API-Key
+// header. An empty API key sends the requests anonymously.
+func APIKeyAuth(apikey string) Authenticator {
+	return AuthenticatorFunc(func(req *http.Request) {
+		if apikey != "" {
+			req.Header.Set("X-Redmine-API-Key", apikey)
+		}
+	})
+}
+
+// BasicAuth authenticates requests with login and password of a Redmine user via HTTP Basic authentication.
+func BasicAuth(login, password string) Authenticator {
+	return AuthenticatorFunc(func(req *http.Request) {
+		req.SetBasicAuth(login, password)
+	})
+}
+
+// AnonymousAuth sends requests without any credentials, f. e. to read public projects.
+func AnonymousAuth() Authenticator {
+	return AuthenticatorFunc(func(req *http.Request) {})
+}
+
+// Impersonate authenticates requests with auth and lets them act on behalf of the user with the given login by
+// setting the X-Redmine-Switch-User header. The user authenticated by auth must be an administrator.
+//
+// Use AsUser to impersonate a user only for single calls.
+func Impersonate(auth Authenticator, login string) Authenticator {
+	return AuthenticatorFunc(func(req *http.Request) {
+		auth.Authenticate(req)
+		req.Header.Set("X-Redmine-Switch-User", login)
+	})
+}
+
+type asUserKey struct{}
+
+// AsUser returns a copy of ctx which makes all calls using it act on behalf of the user with the given login, f. e.
+// to create an issue authored by this user. It takes precedence over an impersonation configured with Impersonate.
+// The client must be authenticated as administrator.
+func AsUser(ctx context.Context, login string) context.Context {
+	return context.WithValue(ctx, asUserKey{}, login)
+}
+
+func asUserFromContext(ctx context.Context) (string, bool) {
+	login, ok := ctx.Value(asUserKey{}).(string)
+	return login, ok && login != ""
+}
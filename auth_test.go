@@ -0,0 +1,74 @@
+package redmine
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_authenticate(t *testing.T) {
+	var actual *http.Request
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		actual = r
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	t.Run("should authenticate with api key", func(t *testing.T) {
+		sut := NewClient(ts.URL, WithAuthenticator(APIKeyAuth("apiKey")))
+
+		err := sut.DeleteIssue(1)
+
+		require.NoError(t, err)
+		assert.Equal(t, "apiKey", actual.Header.Get("X-Redmine-API-Key"))
+		assert.Empty(t, actual.Header.Get("Authorization"))
+	})
+
+	t.Run("should authenticate with login and password", func(t *testing.T) {
+		sut := NewClient(ts.URL, WithAuthenticator(BasicAuth("admin", "secret")))
+
+		err := sut.DeleteIssue(1)
+
+		require.NoError(t, err)
+		login, password, ok := actual.BasicAuth()
+		assert.True(t, ok)
+		assert.Equal(t, "admin", login)
+		assert.Equal(t, "secret", password)
+		assert.Empty(t, actual.Header.Get("X-Redmine-API-Key"))
+	})
+
+	t.Run("should send anonymous requests", func(t *testing.T) {
+		sut := NewClient(ts.URL, WithAuthenticator(AnonymousAuth()))
+
+		err := sut.DeleteIssue(1)
+
+		require.NoError(t, err)
+		assert.Empty(t, actual.Header.Get("X-Redmine-API-Key"))
+		assert.Empty(t, actual.Header.Get("Authorization"))
+	})
+
+	t.Run("should impersonate user for all requests", func(t *testing.T) {
+		sut := NewClient(ts.URL, WithAuthenticator(Impersonate(APIKeyAuth("apiKey"), "jsmith")))
+
+		err := sut.DeleteIssue(1)
+
+		require.NoError(t, err)
+		assert.Equal(t, "apiKey", actual.Header.Get("X-Redmine-API-Key"))
+		assert.Equal(t, "jsmith", actual.Header.Get("X-Redmine-Switch-User"))
+	})
+
+	t.Run("should impersonate user for a single call", func(t *testing.T) {
+		sut := NewClient(ts.URL, WithAuthenticator(Impersonate(APIKeyAuth("apiKey"), "jsmith")))
+
+		err := sut.DeleteIssueContext(AsUser(context.Background(), "jdoe"), 1)
+		require.NoError(t, err)
+		assert.Equal(t, "jdoe", actual.Header.Get("X-Redmine-Switch-User"))
+
+		err = sut.DeleteIssue(1)
+		require.NoError(t, err)
+		assert.Equal(t, "jsmith", actual.Header.Get("X-Redmine-Switch-User"))
+	})
+}
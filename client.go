@@ -10,34 +10,47 @@ import (
 
 type Client struct {
 	endpoint string
-	apikey   string
+	auth     Authenticator
 	Limit    int
 	Offset   int
 	*http.Client
+
+	userAgent   string
+	logger      Logger
+	middlewares []Middleware
+	retryPolicy RetryPolicy
+	rateLimiter *tokenBucket
+	inFlight    semaphore
 }
 
 const NoSetting = -1
 
-var DefaultLimit int = NoSetting
-var DefaultOffset int = NoSetting
+// NewClient creates a client for the Redmine instance at endpoint. Without options the client sends anonymous
+// requests with a new http.Client, see WithAPIKey and WithAuthenticator.
+func NewClient(endpoint string, opts ...Option) *Client {
+	o := &options{pageSize: NoSetting}
+	for _, opt := range opts {
+		opt(o)
+	}
 
-func NewClient(endpoint, apikey string) *Client {
 	return &Client{
-		endpoint: endpoint,
-		apikey:   apikey,
-		Limit:    DefaultLimit,
-		Offset:   DefaultOffset,
-		Client:   http.DefaultClient,
+		endpoint:    endpoint,
+		auth:        o.auth,
+		Limit:       o.pageSize,
+		Offset:      NoSetting,
+		Client:      o.buildHTTPClient(),
+		userAgent:   o.userAgent,
+		logger:      o.logger,
+		retryPolicy: o.retryPolicy,
+		rateLimiter: o.rateLimiter,
+		inFlight:    newSemaphore(o.maxConcurrent),
 	}
 }
 
-func (c *Client) apiKeyParameter() string {
-	return "key=" + c.apikey
-}
-
 func (c *Client) concatParameters(requestParameters ...string) string {
 	cleanedParams := []string{}
 	for _, param := range requestParameters {
+		param = strings.TrimPrefix(param, "&")
 		if param != "" {
 			cleanedParams = append(cleanedParams, param)
 		}
@@ -46,6 +59,23 @@ func (c *Client) concatParameters(requestParameters ...string) string {
 	return strings.Join(cleanedParams, "&")
 }
 
+// pathWithParameters appends the non-empty request parameters as query string to path.
+func (c *Client) pathWithParameters(path string, requestParameters ...string) string {
+	query := c.concatParameters(requestParameters...)
+	if query == "" {
+		return path
+	}
+	return path + "?" + query
+}
+
+// includeParameter returns the include parameter for includes or an empty string if there are none.
+func includeParameter(includes []string) string {
+	if len(includes) == 0 {
+		return ""
+	}
+	return "include=" + strings.Join(includes, ",")
+}
+
 // URLWithFilter return string url by concat endpoint, path and filter
 // err != nil when endpoint can not parse
 func (c *Client) URLWithFilter(path string, f Filter) (string, error) {
@@ -88,3 +118,7 @@ type IdName struct {
 type Id struct {
 	Id int `json:"id"`
 }
+
+type userIdRequest struct {
+	UserId int `json:"user_id"`
+}
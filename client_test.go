@@ -30,6 +30,8 @@ func TestClient_concatParameters(t *testing.T) {
 			"key=value&hello=world"},
 		{"should remove multiple empty parameter in the end and return two parameters", args{[]string{"key=value", "hello=world", "", ""}},
 			"key=value&hello=world"},
+		{"should strip leading ampersands from parameters", args{[]string{"&key=value", "&hello=world&foo=bar"}},
+			"key=value&hello=world&foo=bar"},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -8,7 +8,6 @@ import (
 	"fmt"
 	"io/ioutil"
 	"math/rand"
-	"net/http"
 	"net/url"
 	"os"
 	"os/exec"
@@ -236,9 +235,20 @@ func getConfig() config {
 	return c
 }
 
+func newClient() *redmine.Client {
+	opts := []redmine.Option{
+		redmine.WithAPIKey(conf.Apikey),
+		redmine.WithUserAgent(name + "/" + version),
+	}
+	if conf.Insecure {
+		opts = append(opts, redmine.WithTLSConfig(&tls.Config{InsecureSkipVerify: true}))
+	}
+	return redmine.NewClient(conf.Endpoint, opts...)
+}
+
 func addIssue(subject, description string) {
 	var issue redmine.Issue
-	c := redmine.NewClient(conf.Endpoint, conf.Apikey)
+	c := newClient()
 	issue.ProjectId = conf.Project
 	issue.Subject = subject
 	issue.Description = description
@@ -253,7 +263,7 @@ func createIssue() {
 	if err != nil {
 		fatal("%s\n", err)
 	}
-	c := redmine.NewClient(conf.Endpoint, conf.Apikey)
+	c := newClient()
 	issue.ProjectId = conf.Project
 	_, err = c.CreateIssue(*issue)
 	if err != nil {
@@ -262,7 +272,7 @@ func createIssue() {
 }
 
 func updateIssue(id int) {
-	c := redmine.NewClient(conf.Endpoint, conf.Apikey)
+	c := newClient()
 	issue, err := c.Issue(id)
 	if err != nil {
 		fatal("Failed to update issue: %s\n", err)
@@ -281,7 +291,7 @@ func updateIssue(id int) {
 }
 
 func deleteIssue(id int) {
-	c := redmine.NewClient(conf.Endpoint, conf.Apikey)
+	c := newClient()
 	err := c.DeleteIssue(id)
 	if err != nil {
 		fatal("Failed to delete issue: %s\n", err)
@@ -289,7 +299,7 @@ func deleteIssue(id int) {
 }
 
 func closeIssue(id int) {
-	c := redmine.NewClient(conf.Endpoint, conf.Apikey)
+	c := newClient()
 	issue, err := c.Issue(id)
 	if err != nil {
 		fatal("Failed to update issue: %s\n", err)
@@ -311,7 +321,7 @@ func closeIssue(id int) {
 }
 
 func notesIssue(id int) {
-	c := redmine.NewClient(conf.Endpoint, conf.Apikey)
+	c := newClient()
 	issue, err := c.Issue(id)
 	if err != nil {
 		fatal("Failed to update issue: %s\n", err)
@@ -330,7 +340,7 @@ func notesIssue(id int) {
 }
 
 func showIssue(id int) {
-	c := redmine.NewClient(conf.Endpoint, conf.Apikey)
+	c := newClient()
 	issue, err := c.Issue(id)
 	if err != nil {
 		fatal("Failed to show issue: %s\n", err)
@@ -368,7 +378,7 @@ UpdatedOn: %s
 }
 
 func listIssues(filter *redmine.IssueFilter) {
-	c := redmine.NewClient(conf.Endpoint, conf.Apikey)
+	c := newClient()
 	issues, err := c.IssuesByFilter(filter)
 	if err != nil {
 		fatal("Failed to list issues: %s\n", err)
@@ -380,7 +390,7 @@ func listIssues(filter *redmine.IssueFilter) {
 
 func addProject(name, identifier, description string) {
 	var project redmine.Project
-	c := redmine.NewClient(conf.Endpoint, conf.Apikey)
+	c := newClient()
 	project.Name = name
 	project.Identifier = identifier
 	project.Description = description
@@ -395,7 +405,7 @@ func createProject() {
 	if err != nil {
 		fatal("%s\n", err)
 	}
-	c := redmine.NewClient(conf.Endpoint, conf.Apikey)
+	c := newClient()
 	_, err = c.CreateProject(*project)
 	if err != nil {
 		fatal("Failed to create project: %s\n", err)
@@ -403,7 +413,7 @@ func createProject() {
 }
 
 func updateProject(id int) {
-	c := redmine.NewClient(conf.Endpoint, conf.Apikey)
+	c := newClient()
 	project, err := c.Project(id)
 	if err != nil {
 		fatal("Failed to update project: %s\n", err)
@@ -422,7 +432,7 @@ func updateProject(id int) {
 }
 
 func deleteProject(id int) {
-	c := redmine.NewClient(conf.Endpoint, conf.Apikey)
+	c := newClient()
 	err := c.DeleteProject(id)
 	if err != nil {
 		fatal("Failed to delete project: %s\n", err)
@@ -430,7 +440,7 @@ func deleteProject(id int) {
 }
 
 func showProject(id int) {
-	c := redmine.NewClient(conf.Endpoint, conf.Apikey)
+	c := newClient()
 	project, err := c.Project(id)
 	if err != nil {
 		fatal("Failed to show project: %s\n", err)
@@ -454,7 +464,7 @@ UpdatedOn: %s
 }
 
 func listProjects() {
-	c := redmine.NewClient(conf.Endpoint, conf.Apikey)
+	c := newClient()
 	issues, err := c.Projects()
 	if err != nil {
 		fatal("Failed to list projects: %s\n", err)
@@ -464,8 +474,20 @@ func listProjects() {
 	}
 }
 
+func listProjectTree() {
+	c := newClient()
+	tree, err := c.ProjectTree()
+	if err != nil {
+		fatal("Failed to list projects: %s\n", err)
+	}
+	_ = tree.Walk(func(node *redmine.ProjectNode, depth int) error {
+		fmt.Printf("%4d: %s%s\n", node.Project.Id, strings.Repeat("  ", depth), node.Project.Name)
+		return nil
+	})
+}
+
 func showMembership(id int) {
-	c := redmine.NewClient(conf.Endpoint, conf.Apikey)
+	c := newClient()
 	membership, err := c.Membership(id)
 	if err != nil {
 		fatal("Failed to show membership: %s\n", err)
@@ -489,7 +511,7 @@ Role: `[1:],
 }
 
 func listMemberships(projectId int) {
-	c := redmine.NewClient(conf.Endpoint, conf.Apikey)
+	c := newClient()
 	memberships, err := c.Memberships(projectId)
 	if err != nil {
 		fatal("Failed to list memberships: %s\n", err)
@@ -500,7 +522,7 @@ func listMemberships(projectId int) {
 }
 
 func showUser(id int) {
-	c := redmine.NewClient(conf.Endpoint, conf.Apikey)
+	c := newClient()
 	user, err := c.User(id)
 	if err != nil {
 		fatal("Failed to show user: %s\n", err)
@@ -523,7 +545,7 @@ CreatedOn: %s
 }
 
 func listUsers() {
-	c := redmine.NewClient(conf.Endpoint, conf.Apikey)
+	c := newClient()
 	users, err := c.Users()
 	if err != nil {
 		fatal("Failed to list users: %s\n", err)
@@ -534,20 +556,13 @@ func listUsers() {
 }
 
 func showNews(id int) {
-	c := redmine.NewClient(conf.Endpoint, conf.Apikey)
-	news, err := c.News(id)
+	c := newClient()
+	news, err := c.NewsItem(id)
 	if err != nil {
-		fatal("Failed to show user: %s\n", err)
+		fatal("Failed to show news: %s\n", err)
 	}
 
-	found := -1
-	for i, n := range news {
-		if n.Id == id {
-			found = i
-		}
-	}
-	if found != -1 {
-		fmt.Printf(`
+	fmt.Printf(`
 Id: %d
 Project: %s
 Title: %s
@@ -556,19 +571,16 @@ CreatedOn: %s
 
 %s
 `[1:],
-			news[found].Id,
-			news[found].Project.Name,
-			news[found].Title,
-			news[found].Summary,
-			news[found].CreatedOn,
-			news[found].Description)
-	} else {
-		fatal("Failed to show news: not found\n", nil)
-	}
+		news.Id,
+		news.Project.Name,
+		news.Title,
+		news.Summary,
+		news.CreatedOn,
+		news.Description)
 }
 
 func listNews() {
-	c := redmine.NewClient(conf.Endpoint, conf.Apikey)
+	c := newClient()
 	news, err := c.News(conf.Project)
 	if err != nil {
 		fatal("Failed to list users: %s\n", err)
@@ -579,7 +591,7 @@ func listNews() {
 }
 
 func showVersion(id int) {
-	c := redmine.NewClient(conf.Endpoint, conf.Apikey)
+	c := newClient()
 	ver, err := c.Version(id)
 	if err != nil {
 		fatal("Failed to show version: %s\n", err)
@@ -604,7 +616,7 @@ CreatedOn: %s
 }
 
 func listVersions(projectId int) {
-	c := redmine.NewClient(conf.Endpoint, conf.Apikey)
+	c := newClient()
 	versions, err := c.Versions(projectId)
 	if err != nil {
 		fatal("Failed to list versions: %s\n", err)
@@ -615,7 +627,7 @@ func listVersions(projectId int) {
 }
 
 func showWikiPage(title string) {
-	c := redmine.NewClient(conf.Endpoint, conf.Apikey)
+	c := newClient()
 	page, err := c.WikiPage(conf.Project, title)
 	if err != nil {
 		fatal("Failed to show user: %s\n", err)
@@ -641,7 +653,7 @@ Comments: %s
 }
 
 func listWikiPages() {
-	c := redmine.NewClient(conf.Endpoint, conf.Apikey)
+	c := newClient()
 	pages, err := c.WikiPages(conf.Project)
 	if err != nil {
 		fatal("Failed to list wiki pages: %s\n", err)
@@ -651,11 +663,22 @@ func listWikiPages() {
 	}
 }
 
+func search(query string, options redmine.SearchOptions) {
+	c := newClient()
+	results, err := c.Search(query, options)
+	if err != nil {
+		fatal("Failed to search: %s\n", err)
+	}
+	for _, r := range results {
+		fmt.Printf("%s: %s\n    %s\n", r.Type, r.Title, r.URL)
+	}
+}
+
 func editWikiPage(title string) error {
-	c := redmine.NewClient(conf.Endpoint, conf.Apikey)
+	c := newClient()
 	page, err := c.WikiPage(conf.Project, title)
 	if err != nil {
-		if err.Error() != "Not Found" {
+		if !errors.Is(err, redmine.ErrNotFound) {
 			return fmt.Errorf("Failed to read wiki page for editing: %s\n", err)
 		}
 		page = &redmine.WikiPage{Title: title}
@@ -709,7 +732,12 @@ func initConfigFile(endpoint string, apikey string, project string) {
 	}
 	projectId, err := strconv.Atoi(project)
 	if err != nil {
-		fatal("Project id can not convert to integer: %s\n", err)
+		c := redmine.NewClient(endpoint, redmine.WithAPIKey(apikey), redmine.WithUserAgent(name+"/"+version))
+		p, err := c.ProjectByIdentifier(project)
+		if err != nil {
+			fatal("Failed to find project by identifier: %s\n", err)
+		}
+		projectId = p.Id
 	}
 
 	filename := createConfigFileName()
@@ -813,6 +841,9 @@ Project Commands:
   list     l listing projects.
              $ godmine p l
 
+  tree     t listing all projects as hierarchy.
+             $ godmine p t
+
 Issue Commands:
   add      a create issue with text editor.
              $ godmine i a
@@ -869,6 +900,13 @@ Wiki Commands:
   edit     e edit wiki page griven by title with editor
              $ godmine w e home
 
+Search Commands:
+  all      a search all projects.
+             $ godmine s a keyword
+
+  project  p search the current project.
+             $ godmine s p keyword
+
 Config Commands:
   init     i initialize configuration file.
              $ godmine c i endpoint apikey project
@@ -928,13 +966,6 @@ func main() {
 	}
 
 	conf = getConfig()
-	if conf.Insecure {
-		http.DefaultClient = &http.Client{
-			Transport: &http.Transport{
-				TLSClientConfig: &tls.Config{InsecureSkipVerify: true},
-			},
-		}
-	}
 
 	switch flag.Arg(0) {
 	case "i", "issue":
@@ -1059,6 +1090,9 @@ func main() {
 		case "l", "list":
 			listProjects()
 			break
+		case "t", "tree":
+			listProjectTree()
+			break
 		default:
 			usage()
 		}
@@ -1184,6 +1218,21 @@ func main() {
 			usage()
 
 		}
+	case "s", "search":
+		if flag.NArg() < 3 {
+			usage()
+		}
+		query := strings.Join(flag.Args()[2:], " ")
+		switch flag.Arg(1) {
+		case "a", "all":
+			search(query, redmine.SearchOptions{})
+			break
+		case "p", "project":
+			search(query, redmine.SearchOptions{ProjectId: conf.Project})
+			break
+		default:
+			usage()
+		}
 	default:
 		usage()
 	}
@@ -0,0 +1,126 @@
+package redmine
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+const (
+	// DateLayout is the layout of dates like the due date of an issue in the Redmine API.
+	DateLayout = "2006-01-02"
+	// DateTimeLayout is the layout of timestamps like the creation time of an issue in the Redmine API.
+	DateTimeLayout = time.RFC3339
+)
+
+// dateTimeLayouts contains the layouts accepted when parsing timestamps. Besides RFC 3339 older Redmine versions use
+// the Rails default format.
+var dateTimeLayouts = []string{DateTimeLayout, "2006/01/02 15:04:05 -0700", "2006-01-02 15:04:05 -0700"}
+
+// Date is a calendar day like the start or due date of an issue. The zero value represents a missing date and is
+// marshalled to null.
+type Date struct {
+	time.Time
+}
+
+// NewDate returns the date of the given day.
+func NewDate(year int, month time.Month, day int) Date {
+	return Date{time.Date(year, month, day, 0, 0, 0, 0, time.UTC)}
+}
+
+// ParseDate parses a date in the format 2006-01-02. An empty string results in the zero Date.
+func ParseDate(value string) (Date, error) {
+	if value == "" {
+		return Date{}, nil
+	}
+	t, err := time.Parse(DateLayout, value)
+	if err != nil {
+		return Date{}, fmt.Errorf("invalid date %q: %w", value, err)
+	}
+	return Date{t}, nil
+}
+
+// String formats the date as 2006-01-02 like the Redmine API does, or returns an empty string for the zero Date.
+func (d Date) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.Format(DateLayout)
+}
+
+// MarshalJSON marshals the date as 2006-01-02 or null for the zero Date.
+func (d Date) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON accepts dates in the format 2006-01-02, null and empty strings.
+func (d *Date) UnmarshalJSON(data []byte) error {
+	value, err := unmarshalDateString(data)
+	if err != nil {
+		return err
+	}
+	*d, err = ParseDate(value)
+	return err
+}
+
+// DateTime is a point in time like the creation time of an issue. The zero value represents a missing timestamp and
+// is marshalled to null.
+type DateTime struct {
+	time.Time
+}
+
+// ParseDateTime parses a timestamp in RFC 3339 format as returned by Redmine. An empty string results in the zero
+// DateTime.
+func ParseDateTime(value string) (DateTime, error) {
+	if value == "" {
+		return DateTime{}, nil
+	}
+	var err error
+	for _, layout := range dateTimeLayouts {
+		var t time.Time
+		if t, err = time.Parse(layout, value); err == nil {
+			return DateTime{t}, nil
+		}
+	}
+	return DateTime{}, fmt.Errorf("invalid timestamp %q: %w", value, err)
+}
+
+// String formats the timestamp in RFC 3339 format like the Redmine API does, or returns an empty string for the zero
+// DateTime.
+func (d DateTime) String() string {
+	if d.IsZero() {
+		return ""
+	}
+	return d.Format(DateTimeLayout)
+}
+
+// MarshalJSON marshals the timestamp in RFC 3339 format or null for the zero DateTime.
+func (d DateTime) MarshalJSON() ([]byte, error) {
+	if d.IsZero() {
+		return []byte("null"), nil
+	}
+	return json.Marshal(d.String())
+}
+
+// UnmarshalJSON accepts timestamps in the formats of ParseDateTime, null and empty strings.
+func (d *DateTime) UnmarshalJSON(data []byte) error {
+	value, err := unmarshalDateString(data)
+	if err != nil {
+		return err
+	}
+	*d, err = ParseDateTime(value)
+	return err
+}
+
+func unmarshalDateString(data []byte) (string, error) {
+	if bytes.Equal(data, []byte("null")) {
+		return "", nil
+	}
+	var value string
+	err := json.Unmarshal(data, &value)
+	return value, err
+}
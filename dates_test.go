@@ -0,0 +1,97 @@
+package redmine
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestDate(t *testing.T) {
+	t.Run("should unmarshal dates, null and empty strings", func(t *testing.T) {
+		var actual struct {
+			Start Date `json:"start"`
+			Due   Date `json:"due"`
+			Other Date `json:"other"`
+		}
+
+		err := json.Unmarshal([]byte(`{"start": "2021-03-05", "due": null, "other": ""}`), &actual)
+
+		require.NoError(t, err)
+		assert.Equal(t, NewDate(2021, time.March, 5), actual.Start)
+		assert.True(t, actual.Due.IsZero())
+		assert.True(t, actual.Other.IsZero())
+	})
+
+	t.Run("should marshal dates and null", func(t *testing.T) {
+		actual, err := json.Marshal(map[string]Date{"start": NewDate(2021, time.March, 5), "due": {}})
+
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"start": "2021-03-05", "due": null}`, string(actual))
+	})
+
+	t.Run("should fail on invalid dates", func(t *testing.T) {
+		var actual Date
+
+		err := json.Unmarshal([]byte(`"05.03.2021"`), &actual)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `invalid date "05.03.2021"`)
+	})
+
+	t.Run("should format like the Redmine API", func(t *testing.T) {
+		actual, err := ParseDate("2021-03-05")
+
+		require.NoError(t, err)
+		assert.Equal(t, "2021-03-05", actual.String())
+		assert.Equal(t, "", Date{}.String())
+	})
+}
+
+func TestDateTime(t *testing.T) {
+	t.Run("should unmarshal timestamps, null and empty strings", func(t *testing.T) {
+		var actual struct {
+			Created DateTime `json:"created"`
+			Updated DateTime `json:"updated"`
+			Closed  DateTime `json:"closed"`
+			Legacy  DateTime `json:"legacy"`
+		}
+
+		err := json.Unmarshal([]byte(`{"created": "2021-02-23T14:20:48Z", "updated": "2021-02-23T15:20:48+01:00", "closed": null, "legacy": "2021/02/23 15:20:48 +0100"}`), &actual)
+
+		require.NoError(t, err)
+		expected := time.Date(2021, 2, 23, 14, 20, 48, 0, time.UTC)
+		assert.True(t, expected.Equal(actual.Created.Time))
+		assert.True(t, expected.Equal(actual.Updated.Time))
+		assert.True(t, expected.Equal(actual.Legacy.Time))
+		assert.True(t, actual.Closed.IsZero())
+	})
+
+	t.Run("should marshal timestamps and null", func(t *testing.T) {
+		actual, err := json.Marshal(map[string]DateTime{
+			"created": {time.Date(2021, 2, 23, 14, 20, 48, 0, time.UTC)},
+			"closed":  {},
+		})
+
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"created": "2021-02-23T14:20:48Z", "closed": null}`, string(actual))
+	})
+
+	t.Run("should fail on invalid timestamps", func(t *testing.T) {
+		var actual DateTime
+
+		err := json.Unmarshal([]byte(`"yesterday"`), &actual)
+
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), `invalid timestamp "yesterday"`)
+	})
+
+	t.Run("should format like the Redmine API", func(t *testing.T) {
+		actual, err := ParseDateTime("2021-02-23T14:20:48Z")
+
+		require.NoError(t, err)
+		assert.Equal(t, "2021-02-23T14:20:48Z", actual.String())
+		assert.Equal(t, "", DateTime{}.String())
+	})
+}
@@ -0,0 +1,143 @@
+package redmine
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// Sentinel errors which can be used with errors.Is to classify errors returned by the client without having to know
+// the concrete error type.
+var (
+	// ErrNotFound matches every *NotFoundError.
+	ErrNotFound = errors.New("not found")
+	// ErrValidation matches every *ValidationError.
+	ErrValidation = errors.New("validation failed")
+	// ErrAuth matches every *AuthError.
+	ErrAuth = errors.New("authentication or authorization failed")
+	// ErrServer matches every *ServerError.
+	ErrServer = errors.New("unexpected server response")
+	// ErrProjectNotActive matches every *ProjectStatusError.
+	ErrProjectNotActive = errors.New("project is not active")
+)
+
+// NotFoundError is returned when Redmine answers with HTTP 404 Not Found, f. e. because the requested issue does not
+// exist or is not visible to the current user.
+type NotFoundError struct {
+	// Path contains the URL path of the resource that could not be found.
+	Path string
+}
+
+func (e *NotFoundError) Error() string {
+	if e.Path == "" {
+		return "resource was not found"
+	}
+	return fmt.Sprintf("resource %s was not found", e.Path)
+}
+
+// Is reports whether target is ErrNotFound.
+func (e *NotFoundError) Is(target error) bool {
+	return target == ErrNotFound
+}
+
+// ValidationError is returned when Redmine rejects a request, usually with HTTP 422 Unprocessable Entity. Errors
+// contains the individual messages of the "errors" array of the response body.
+type ValidationError struct {
+	StatusCode int
+	Errors     []string
+}
+
+func (e *ValidationError) Error() string {
+	if len(e.Errors) == 0 {
+		return fmt.Sprintf("validation failed with HTTP %d", e.StatusCode)
+	}
+	return strings.Join(e.Errors, "\n")
+}
+
+// Is reports whether target is ErrValidation.
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// AuthError is returned when Redmine answers with HTTP 401 Unauthorized or HTTP 403 Forbidden.
+type AuthError struct {
+	StatusCode int
+}
+
+func (e *AuthError) Error() string {
+	if e.StatusCode == http.StatusForbidden {
+		return "access to the requested resource is forbidden"
+	}
+	return "authentication against redmine failed"
+}
+
+// Is reports whether target is ErrAuth.
+func (e *AuthError) Is(target error) bool {
+	return target == ErrAuth
+}
+
+// ServerError is returned for every other unsuccessful HTTP response. Body contains the raw response body.
+type ServerError struct {
+	StatusCode int
+	Body       []byte
+}
+
+func (e *ServerError) Error() string {
+	return fmt.Sprintf("unexpected HTTP status %d %s", e.StatusCode, http.StatusText(e.StatusCode))
+}
+
+// Is reports whether target is ErrServer.
+func (e *ServerError) Is(target error) bool {
+	return target == ErrServer
+}
+
+// ProjectStatusError is returned by CreateMembershipByProjectID, which checks the status of the project before sending
+// the membership, if the project is closed. Archived projects are not visible through the API at all, so Redmine
+// answers with an AuthError instead.
+type ProjectStatusError struct {
+	ProjectId int
+	Status    ProjectStatus
+}
+
+func (e *ProjectStatusError) Error() string {
+	return fmt.Sprintf("project %d is %s", e.ProjectId, e.Status)
+}
+
+// Is reports whether target is ErrProjectNotActive.
+func (e *ProjectStatusError) Is(target error) bool {
+	return target == ErrProjectNotActive
+}
+
+// errorFromResponse consumes the body of an unsuccessful response and converts it into one of the typed errors above.
+func errorFromResponse(res *http.Response) error {
+	body, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return err
+	}
+
+	switch res.StatusCode {
+	case http.StatusNotFound:
+		path := ""
+		if res.Request != nil && res.Request.URL != nil {
+			path = res.Request.URL.Path
+		}
+		return &NotFoundError{Path: path}
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return &AuthError{StatusCode: res.StatusCode}
+	case http.StatusUnprocessableEntity:
+		var er errorsResult
+		_ = json.Unmarshal(body, &er)
+		return &ValidationError{StatusCode: res.StatusCode, Errors: er.Errors}
+	}
+
+	if res.StatusCode >= 400 && res.StatusCode < 500 {
+		var er errorsResult
+		if json.Unmarshal(body, &er) == nil && len(er.Errors) > 0 {
+			return &ValidationError{StatusCode: res.StatusCode, Errors: er.Errors}
+		}
+	}
+	return &ServerError{StatusCode: res.StatusCode, Body: body}
+}
@@ -0,0 +1,92 @@
+package redmine
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func Test_errorFromResponse(t *testing.T) {
+	tests := []struct {
+		name       string
+		statusCode int
+		body       string
+		sentinel   error
+	}{
+		{"should return NotFoundError for HTTP 404", http.StatusNotFound, "", ErrNotFound},
+		{"should return AuthError for HTTP 401", http.StatusUnauthorized, "", ErrAuth},
+		{"should return AuthError for HTTP 403", http.StatusForbidden, "", ErrAuth},
+		{"should return ValidationError for HTTP 422", http.StatusUnprocessableEntity, `{"errors": ["Name cannot be blank"]}`, ErrValidation},
+		{"should return ValidationError for other client errors with error messages", http.StatusConflict, `{"errors": ["Conflict"]}`, ErrValidation},
+		{"should return ServerError for other client errors without error messages", http.StatusConflict, `<html></html>`, ErrServer},
+		{"should return ServerError for HTTP 500", http.StatusInternalServerError, "boom", ErrServer},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.statusCode)
+				_, _ = fmt.Fprint(w, tt.body)
+			}))
+			defer ts.Close()
+
+			res, err := http.Get(ts.URL + "/issues/1.json")
+			require.NoError(t, err)
+			defer res.Body.Close()
+
+			actual := errorFromResponse(res)
+
+			assert.True(t, errors.Is(actual, tt.sentinel))
+		})
+	}
+
+	t.Run("should keep individual validation messages", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = fmt.Fprint(w, `{"errors": ["Name cannot be blank", "Identifier is too short"]}`)
+		}))
+		defer ts.Close()
+
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		_, err := sut.CreateProject(Project{})
+
+		var validationErr *ValidationError
+		require.True(t, errors.As(err, &validationErr))
+		assert.Equal(t, []string{"Name cannot be blank", "Identifier is too short"}, validationErr.Errors)
+		assert.Equal(t, "Name cannot be blank\nIdentifier is too short", err.Error())
+	})
+
+	t.Run("should keep status code and raw body of server errors", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+			_, _ = fmt.Fprint(w, "bad gateway")
+		}))
+		defer ts.Close()
+
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		_, err := sut.Issue(1)
+
+		var serverErr *ServerError
+		require.True(t, errors.As(err, &serverErr))
+		assert.Equal(t, http.StatusBadGateway, serverErr.StatusCode)
+		assert.Equal(t, []byte("bad gateway"), serverErr.Body)
+	})
+
+	t.Run("should return NotFoundError with the path of the missing resource", func(t *testing.T) {
+		ts := httptest.NewServer(http.NotFoundHandler())
+		defer ts.Close()
+
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		err := sut.DeleteMembership(42)
+
+		var notFoundErr *NotFoundError
+		require.True(t, errors.As(err, &notFoundErr))
+		assert.Equal(t, "/memberships/42.json", notFoundErr.Path)
+	})
+}
@@ -0,0 +1,64 @@
+package redmine
+
+import (
+	"context"
+	"strconv"
+)
+
+type filesResult struct {
+	Files []File `json:"files"`
+}
+
+type fileRequest struct {
+	File fileCreate `json:"file"`
+}
+
+type fileCreate struct {
+	Token       string `json:"token"`
+	VersionId   int    `json:"version_id,omitempty"`
+	Filename    string `json:"filename,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// File contains a file published in the Files section of a project.
+//
+// See also: https://www.redmine.org/projects/redmine/wiki/Rest_Files
+type File struct {
+	Attachment
+	// Version contains the version the file belongs to or nil if it belongs to the project itself.
+	Version   *IdName `json:"version,omitempty"`
+	Digest    string  `json:"digest"`
+	Downloads int     `json:"downloads"`
+}
+
+// Files returns the files of the project with the id projectId.
+func (c *Client) Files(projectId int) ([]File, error) {
+	return c.FilesContext(context.Background(), projectId)
+}
+
+// FilesContext is like Files but binds the request to ctx.
+func (c *Client) FilesContext(ctx context.Context, projectId int) ([]File, error) {
+	var r filesResult
+	err := c.get(ctx, "/projects/"+strconv.Itoa(projectId)+"/files.json", &r)
+	if err != nil {
+		return nil, err
+	}
+	return r.Files, nil
+}
+
+// CreateFile publishes a file previously uploaded with Upload or UploadFrom in the Files section of the project with
+// the id projectId. Filename and Description of upload are used if set. versionId assigns the file to a version of
+// the project; 0 adds it to the project itself.
+func (c *Client) CreateFile(projectId int, upload Upload, versionId int) error {
+	return c.CreateFileContext(context.Background(), projectId, upload, versionId)
+}
+
+// CreateFileContext is like CreateFile but binds the request to ctx.
+func (c *Client) CreateFileContext(ctx context.Context, projectId int, upload Upload, versionId int) error {
+	return c.post(ctx, "/projects/"+strconv.Itoa(projectId)+"/files.json", fileRequest{File: fileCreate{
+		Token:       upload.Token,
+		VersionId:   versionId,
+		Filename:    upload.Filename,
+		Description: upload.Description,
+	}}, nil)
+}
@@ -0,0 +1,81 @@
+package redmine
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_Files(t *testing.T) {
+	t.Run("should parse the files of a project", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/projects/1/files.json", r.URL.Path)
+			_, _ = fmt.Fprintln(w, `{
+  "files": [
+    {
+      "id": 12,
+      "filename": "release-1.0.tar.gz",
+      "filesize": 1048576,
+      "content_type": "application/gzip",
+      "description": "Release 1.0",
+      "content_url": "https://redmine.example.com/attachments/download/12/release-1.0.tar.gz",
+      "author": {"id": 1, "name": "Redmine Admin"},
+      "created_on": "2021-03-01T10:00:00Z",
+      "version": {"id": 2, "name": "1.0"},
+      "digest": "6f5902ac237024bdd0c176cb93063dc4",
+      "downloads": 3
+    }
+  ]
+}`)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		actual, err := sut.Files(1)
+
+		require.NoError(t, err)
+		require.Len(t, actual, 1)
+		assert.Equal(t, 12, actual[0].Id)
+		assert.Equal(t, "release-1.0.tar.gz", actual[0].Filename)
+		assert.Equal(t, int64(1048576), actual[0].Filesize)
+		assert.Equal(t, &IdName{Id: 2, Name: "1.0"}, actual[0].Version)
+		assert.Equal(t, "6f5902ac237024bdd0c176cb93063dc4", actual[0].Digest)
+		assert.Equal(t, 3, actual[0].Downloads)
+	})
+}
+
+func TestClient_CreateFile(t *testing.T) {
+	t.Run("should publish the upload for a version", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "/projects/1/files.json", r.URL.Path)
+			body, _ := ioutil.ReadAll(r.Body)
+			assert.JSONEq(t, `{"file": {"token": "12.abc", "version_id": 2, "filename": "release-1.0.tar.gz", "description": "Release 1.0"}}`, string(body))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		err := sut.CreateFile(1, Upload{Token: "12.abc", Filename: "release-1.0.tar.gz", Description: "Release 1.0"}, 2)
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should omit the version for project files", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			assert.JSONEq(t, `{"file": {"token": "12.abc"}}`, string(body))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		err := sut.CreateFile(1, Upload{Token: "12.abc"}, 0)
+
+		require.NoError(t, err)
+	})
+}
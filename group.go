@@ -0,0 +1,126 @@
+package redmine
+
+import (
+	"context"
+	"strconv"
+)
+
+type groupsResult struct {
+	Groups []Group `json:"groups"`
+}
+
+type groupResult struct {
+	Group Group `json:"group"`
+}
+
+type groupRequest struct {
+	Group Group `json:"group"`
+}
+
+// Group contains a Redmine API group object. Users and Memberships are only filled if requested by the corresponding
+// include of Group().
+//
+// See also: https://www.redmine.org/projects/redmine/wiki/Rest_Groups
+type Group struct {
+	Id int `json:"id"`
+	// Name is mandatory when creating a group. UpdateGroup keeps the current name if it is empty.
+	Name string `json:"name,omitempty"`
+	// UserIds sets the members of the group when creating or updating a group. It is never returned by Redmine, see
+	// Users.
+	UserIds      []int          `json:"user_ids,omitempty"`
+	Users        []IdName       `json:"users,omitempty"`
+	Memberships  []Membership   `json:"memberships,omitempty"`
+	CustomFields []*CustomField `json:"custom_fields,omitempty"`
+}
+
+const (
+	GroupIncludeUsers       string = "users"
+	GroupIncludeMemberships string = "memberships"
+)
+
+// Groups returns all groups. This requires admin privileges.
+func (c *Client) Groups() ([]Group, error) {
+	return c.GroupsContext(context.Background())
+}
+
+// GroupsContext is like Groups but binds the request to ctx.
+func (c *Client) GroupsContext(ctx context.Context) ([]Group, error) {
+	var r groupsResult
+	err := c.get(ctx, c.pathWithParameters("/groups.json", c.getPaginationClause()), &r)
+	if err != nil {
+		return nil, err
+	}
+	return r.Groups, nil
+}
+
+// Group returns a single group. includes may contain GroupIncludeUsers and GroupIncludeMemberships to fetch the users
+// and project memberships of the group, too.
+func (c *Client) Group(id int, includes ...string) (*Group, error) {
+	return c.GroupContext(context.Background(), id, includes...)
+}
+
+// GroupContext is like Group but binds the request to ctx.
+func (c *Client) GroupContext(ctx context.Context, id int, includes ...string) (*Group, error) {
+	var r groupResult
+	err := c.get(ctx, c.pathWithParameters("/groups/"+strconv.Itoa(id)+".json", includeParameter(includes)), &r)
+	if err != nil {
+		return nil, err
+	}
+	return &r.Group, nil
+}
+
+// CreateGroup creates a group with the name group.Name and the members group.UserIds.
+func (c *Client) CreateGroup(group Group) (*Group, error) {
+	return c.CreateGroupContext(context.Background(), group)
+}
+
+// CreateGroupContext is like CreateGroup but binds the request to ctx.
+func (c *Client) CreateGroupContext(ctx context.Context, group Group) (*Group, error) {
+	var r groupResult
+	err := c.post(ctx, "/groups.json", groupRequest{Group: group}, &r)
+	if err != nil {
+		return nil, err
+	}
+	return &r.Group, nil
+}
+
+// UpdateGroup updates the group with the id group.Id. Empty fields keep their current values; if group.UserIds is not
+// empty, it replaces the members of the group.
+func (c *Client) UpdateGroup(group Group) error {
+	return c.UpdateGroupContext(context.Background(), group)
+}
+
+// UpdateGroupContext is like UpdateGroup but binds the request to ctx.
+func (c *Client) UpdateGroupContext(ctx context.Context, group Group) error {
+	return c.put(ctx, "/groups/"+strconv.Itoa(group.Id)+".json", groupRequest{Group: group}, nil)
+}
+
+// DeleteGroup deletes the group with the given id.
+func (c *Client) DeleteGroup(id int) error {
+	return c.DeleteGroupContext(context.Background(), id)
+}
+
+// DeleteGroupContext is like DeleteGroup but binds the request to ctx.
+func (c *Client) DeleteGroupContext(ctx context.Context, id int) error {
+	return c.delete(ctx, "/groups/"+strconv.Itoa(id)+".json")
+}
+
+// AddUserToGroup adds the user with the id userId to the group with the id groupId.
+func (c *Client) AddUserToGroup(groupId int, userId int) error {
+	return c.AddUserToGroupContext(context.Background(), groupId, userId)
+}
+
+// AddUserToGroupContext is like AddUserToGroup but binds the request to ctx.
+func (c *Client) AddUserToGroupContext(ctx context.Context, groupId int, userId int) error {
+	return c.post(ctx, "/groups/"+strconv.Itoa(groupId)+"/users.json", userIdRequest{UserId: userId}, nil)
+}
+
+// RemoveUserFromGroup removes the user with the id userId from the group with the id groupId.
+func (c *Client) RemoveUserFromGroup(groupId int, userId int) error {
+	return c.RemoveUserFromGroupContext(context.Background(), groupId, userId)
+}
+
+// RemoveUserFromGroupContext is like RemoveUserFromGroup but binds the request to ctx.
+func (c *Client) RemoveUserFromGroupContext(ctx context.Context, groupId int, userId int) error {
+	return c.delete(ctx, "/groups/"+strconv.Itoa(groupId)+"/users/"+strconv.Itoa(userId)+".json")
+}
@@ -0,0 +1,175 @@
+package redmine
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_Group(t *testing.T) {
+	t.Run("should request includes and parse users and memberships", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/groups/5.json", r.URL.Path)
+			assert.Equal(t, "users,memberships", r.URL.Query().Get("include"))
+			_, _ = fmt.Fprintln(w, `{
+  "group": {
+    "id": 5,
+    "name": "Developers",
+    "users": [{"id": 2, "name": "John Smith"}],
+    "memberships": [{"id": 9, "project": {"id": 1, "name": "example project"}, "roles": [{"id": 4, "name": "Developer"}]}]
+  }
+}`)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		actual, err := sut.Group(5, GroupIncludeUsers, GroupIncludeMemberships)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Developers", actual.Name)
+		assert.Equal(t, []IdName{{Id: 2, Name: "John Smith"}}, actual.Users)
+		require.Len(t, actual.Memberships, 1)
+		assert.Equal(t, "example project", actual.Memberships[0].Project.Name)
+		assert.Equal(t, []IdName{{Id: 4, Name: "Developer"}}, actual.Memberships[0].Roles)
+	})
+
+	t.Run("should not send an include parameter without includes", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "", r.URL.RawQuery)
+			_, _ = fmt.Fprintln(w, `{"group": {"id": 5, "name": "Developers"}}`)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		actual, err := sut.Group(5)
+
+		require.NoError(t, err)
+		assert.Empty(t, actual.Users)
+	})
+}
+
+func TestClient_Groups(t *testing.T) {
+	t.Run("should list groups", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/groups.json", r.URL.Path)
+			_, _ = fmt.Fprintln(w, `{"groups": [{"id": 5, "name": "Developers"}, {"id": 6, "name": "Reporters"}]}`)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		actual, err := sut.Groups()
+
+		require.NoError(t, err)
+		assert.Equal(t, []Group{{Id: 5, Name: "Developers"}, {Id: 6, Name: "Reporters"}}, actual)
+	})
+}
+
+func TestClient_CreateGroup(t *testing.T) {
+	t.Run("should send name and user ids", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "/groups.json", r.URL.Path)
+			body, _ := ioutil.ReadAll(r.Body)
+			assert.JSONEq(t, `{"group": {"id": 0, "name": "Developers", "user_ids": [2, 3]}}`, string(body))
+			w.WriteHeader(http.StatusCreated)
+			_, _ = fmt.Fprintln(w, `{"group": {"id": 5, "name": "Developers"}}`)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		actual, err := sut.CreateGroup(Group{Name: "Developers", UserIds: []int{2, 3}})
+
+		require.NoError(t, err)
+		assert.Equal(t, 5, actual.Id)
+	})
+}
+
+func TestClient_UpdateGroup(t *testing.T) {
+	t.Run("should put the group", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPut, r.Method)
+			assert.Equal(t, "/groups/5.json", r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		err := sut.UpdateGroup(Group{Id: 5, Name: "Devs"})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should not send an empty name when replacing the members only", func(t *testing.T) {
+		var body struct {
+			Group map[string]interface{} `json:"group"`
+		}
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		err := sut.UpdateGroup(Group{Id: 3, UserIds: []int{1}})
+
+		require.NoError(t, err)
+		assert.NotContains(t, body.Group, "name")
+		assert.Equal(t, []interface{}{1.0}, body.Group["user_ids"])
+	})
+}
+
+func TestClient_DeleteGroup(t *testing.T) {
+	t.Run("should delete the group", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodDelete, r.Method)
+			assert.Equal(t, "/groups/5.json", r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		err := sut.DeleteGroup(5)
+
+		require.NoError(t, err)
+	})
+}
+
+func TestClient_AddUserToGroup(t *testing.T) {
+	t.Run("should post the user id", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "/groups/5/users.json", r.URL.Path)
+			var body map[string]int
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, map[string]int{"user_id": 2}, body)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		err := sut.AddUserToGroup(5, 2)
+
+		require.NoError(t, err)
+	})
+}
+
+func TestClient_RemoveUserFromGroup(t *testing.T) {
+	t.Run("should delete the user from the group", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodDelete, r.Method)
+			assert.Equal(t, "/groups/5/users/2.json", r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		err := sut.RemoveUserFromGroup(5, 2)
+
+		require.NoError(t, err)
+	})
+}
@@ -1,10 +1,9 @@
 package redmine
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
-	"net/http"
 	"strconv"
 	"strings"
 )
@@ -24,52 +23,123 @@ type issuesResult struct {
 	Limit      uint    `json:"limit"`
 }
 
+// JournalPropertyKind tells which kind of property a JournalDetails entry describes.
+type JournalPropertyKind string
+
+const (
+	// JournalPropertyAttr marks changes of issue attributes. Name contains the attribute, f. e. "status_id".
+	JournalPropertyAttr JournalPropertyKind = "attr"
+	// JournalPropertyCustomField marks changes of custom fields. Name contains the id of the custom field.
+	JournalPropertyCustomField JournalPropertyKind = "cf"
+	// JournalPropertyAttachment marks added or removed attachments. Name contains the id of the attachment.
+	JournalPropertyAttachment JournalPropertyKind = "attachment"
+	// JournalPropertyRelation marks added or removed relations. Name contains the relation type.
+	JournalPropertyRelation JournalPropertyKind = "relation"
+)
+
 type JournalDetails struct {
-	Property string `json:"property"`
-	Name     string `json:"name"`
-	OldValue string `json:"old_value"`
-	NewValue string `json:"new_value"`
+	Property JournalPropertyKind `json:"property"`
+	Name     string              `json:"name"`
+	OldValue string              `json:"old_value"`
+	NewValue string              `json:"new_value"`
 }
 type Journal struct {
-	Id        int              `json:"id"`
-	User      *IdName          `json:"user"`
-	Notes     string           `json:"notes"`
-	CreatedOn string           `json:"created_on"`
-	Details   []JournalDetails `json:"details"`
+	Id           int              `json:"id"`
+	User         *IdName          `json:"user"`
+	Notes        string           `json:"notes"`
+	PrivateNotes bool             `json:"private_notes"`
+	CreatedOn    DateTime         `json:"created_on"`
+	Details      []JournalDetails `json:"details"`
+}
+
+type journalRequest struct {
+	Journal journalUpdate `json:"journal"`
+}
+
+type journalUpdate struct {
+	// Notes is omitted if empty because Redmine deletes journals with blank notes and without details.
+	Notes string `json:"notes,omitempty"`
+	// PrivateNotes is omitted if nil so that changing the notes keeps their visibility.
+	PrivateNotes *bool `json:"private_notes,omitempty"`
 }
 
 type Issue struct {
-	Id           int            `json:"id"`
-	Subject      string         `json:"subject"`
-	Description  string         `json:"description"`
-	ProjectId    int            `json:"project_id"`
-	Project      *IdName        `json:"project"`
-	TrackerId    int            `json:"tracker_id"`
-	Tracker      *IdName        `json:"tracker"`
-	ParentId     int            `json:"parent_issue_id,omitempty"`
-	Parent       *Id            `json:"parent"`
-	StatusId     int            `json:"status_id"`
-	Status       *IdName        `json:"status"`
-	PriorityId   int            `json:"priority_id,omitempty"`
-	Priority     *IdName        `json:"priority"`
-	Author       *IdName        `json:"author"`
-	FixedVersion *IdName        `json:"fixed_version"`
-	AssignedTo   *IdName        `json:"assigned_to"`
-	Category     *IdName        `json:"category"`
-	CategoryId   int            `json:"category_id"`
-	Notes        string         `json:"notes"`
+	Id           int     `json:"id"`
+	Subject      string  `json:"subject"`
+	Description  string  `json:"description"`
+	ProjectId    int     `json:"project_id"`
+	Project      *IdName `json:"project"`
+	TrackerId    int     `json:"tracker_id"`
+	Tracker      *IdName `json:"tracker"`
+	ParentId     int     `json:"parent_issue_id,omitempty"`
+	Parent       *Id     `json:"parent"`
+	StatusId     int     `json:"status_id"`
+	Status       *IdName `json:"status"`
+	PriorityId   int     `json:"priority_id,omitempty"`
+	Priority     *IdName `json:"priority"`
+	Author       *IdName `json:"author"`
+	FixedVersion *IdName `json:"fixed_version"`
+	AssignedTo   *IdName `json:"assigned_to"`
+	Category     *IdName `json:"category"`
+	CategoryId   int     `json:"category_id"`
+	Notes        string  `json:"notes"`
+	// PrivateNotes makes Notes visible only to users with the permission to view private notes when updating an
+	// issue.
+	PrivateNotes bool           `json:"private_notes,omitempty"`
 	StatusDate   string         `json:"status_date"`
-	CreatedOn    string         `json:"created_on"`
-	UpdatedOn    string         `json:"updated_on"`
-	StartDate    string         `json:"start_date"`
-	DueDate      string         `json:"due_date"`
-	ClosedOn     string         `json:"closed_on"`
+	CreatedOn    DateTime       `json:"created_on"`
+	UpdatedOn    DateTime       `json:"updated_on"`
+	StartDate    Date           `json:"start_date"`
+	DueDate      Date           `json:"due_date"`
+	ClosedOn     DateTime       `json:"closed_on"`
 	CustomFields []*CustomField `json:"custom_fields,omitempty"`
 	Uploads      []*Upload      `json:"uploads"`
 	DoneRatio    float32        `json:"done_ratio"`
 	Journals     []*Journal     `json:"journals"`
+	// Watchers is only filled if requested with IssueIncludeWatchers.
+	Watchers []IdName `json:"watchers,omitempty"`
+	// WatcherUserIds adds watchers when creating an issue. Redmine ignores it on updates, see AddWatcher.
+	WatcherUserIds []int `json:"watcher_user_ids,omitempty"`
+	// Attachments is only filled if requested with IssueIncludeAttachments.
+	Attachments []Attachment `json:"attachments,omitempty"`
+	// Children is only filled if requested with IssueIncludeChildren.
+	Children []IssueChild `json:"children,omitempty"`
+	// Relations is only filled if requested with IssueIncludeRelations.
+	Relations []IssueRelation `json:"relations,omitempty"`
+	// Changesets is only filled if requested with IssueIncludeChangesets.
+	Changesets []Changeset `json:"changesets,omitempty"`
+	// AllowedStatuses contains the statuses the current user may set and is only filled if requested with
+	// IssueIncludeAllowedStatuses (since Redmine 5.0).
+	AllowedStatuses []IssueStatus `json:"allowed_statuses,omitempty"`
+}
+
+// IssueChild is a subtask of an issue including its own subtasks.
+type IssueChild struct {
+	Id       int          `json:"id"`
+	Tracker  *IdName      `json:"tracker"`
+	Subject  string       `json:"subject"`
+	Children []IssueChild `json:"children,omitempty"`
 }
 
+// Changeset is a repository commit associated with an issue.
+type Changeset struct {
+	Revision    string   `json:"revision"`
+	User        *IdName  `json:"user"`
+	Comments    string   `json:"comments"`
+	CommittedOn DateTime `json:"committed_on"`
+}
+
+// Includes for IssueWithIncludes which fetch additional data of an issue.
+const (
+	IssueIncludeChildren        string = "children"
+	IssueIncludeAttachments     string = "attachments"
+	IssueIncludeRelations       string = "relations"
+	IssueIncludeChangesets      string = "changesets"
+	IssueIncludeJournals        string = "journals"
+	IssueIncludeWatchers        string = "watchers"
+	IssueIncludeAllowedStatuses string = "allowed_statuses"
+)
+
 type IssueFilter struct {
 	ProjectId    string
 	SubprojectId string
@@ -89,80 +159,178 @@ type CustomField struct {
 }
 
 func (c *Client) IssuesOf(projectId int) ([]Issue, error) {
-	issues, err := getIssues(c, "/issues.json?project_id="+strconv.Itoa(projectId)+"&key="+c.apikey+c.getPaginationClause())
-
-	if err != nil {
-		return nil, err
-	}
+	return c.IssuesOfContext(context.Background(), projectId)
+}
 
-	return issues, nil
+func (c *Client) IssuesOfContext(ctx context.Context, projectId int) ([]Issue, error) {
+	return getIssues(ctx, c, "project_id="+strconv.Itoa(projectId))
 }
 
 func (c *Client) Issue(id int) (*Issue, error) {
-	return getOneIssue(c, id, nil)
+	return c.IssueContext(context.Background(), id)
+}
+
+func (c *Client) IssueContext(ctx context.Context, id int) (*Issue, error) {
+	return getOneIssue(ctx, c, id, nil)
 }
 
 func (c *Client) IssueWithArgs(id int, args map[string]string) (*Issue, error) {
-	return getOneIssue(c, id, args)
+	return c.IssueWithArgsContext(context.Background(), id, args)
 }
 
-func (c *Client) IssuesByQuery(queryId int) ([]Issue, error) {
-	issues, err := getIssues(c, "/issues.json?query_id="+strconv.Itoa(queryId)+"&key="+c.apikey+c.getPaginationClause())
+func (c *Client) IssueWithArgsContext(ctx context.Context, id int, args map[string]string) (*Issue, error) {
+	return getOneIssue(ctx, c, id, args)
+}
 
+// IssueWithIncludes returns the issue with the given id together with the additional data requested by includes,
+// f. e. IssueIncludeJournals and IssueIncludeAllowedStatuses.
+func (c *Client) IssueWithIncludes(id int, includes ...string) (*Issue, error) {
+	return c.IssueWithIncludesContext(context.Background(), id, includes...)
+}
+
+// IssueWithIncludesContext is like IssueWithIncludes but binds the request to ctx.
+func (c *Client) IssueWithIncludesContext(ctx context.Context, id int, includes ...string) (*Issue, error) {
+	var args map[string]string
+	if len(includes) > 0 {
+		args = map[string]string{"include": strings.Join(includes, ",")}
+	}
+	return getOneIssue(ctx, c, id, args)
+}
+
+// IssueJournals returns the history of the issue with the given id, i. e. its notes and changes, oldest first.
+func (c *Client) IssueJournals(issueId int) ([]*Journal, error) {
+	return c.IssueJournalsContext(context.Background(), issueId)
+}
+
+// IssueJournalsContext is like IssueJournals but binds the request to ctx.
+func (c *Client) IssueJournalsContext(ctx context.Context, issueId int) ([]*Journal, error) {
+	issue, err := c.IssueWithIncludesContext(ctx, issueId, IssueIncludeJournals)
 	if err != nil {
 		return nil, err
 	}
-	return issues, nil
+	return issue.Journals, nil
+}
+
+// UpdateJournal changes the notes of the journal with the id journal.Id to journal.Notes. The visibility of the notes
+// is kept, use SetJournalPrivate to change it. An empty journal.Notes keeps the current notes, too, because Redmine
+// deletes a journal with blank notes and without details. This requires Redmine 5.0 or later.
+func (c *Client) UpdateJournal(journal Journal) error {
+	return c.UpdateJournalContext(context.Background(), journal)
+}
+
+// UpdateJournalContext is like UpdateJournal but binds the request to ctx.
+func (c *Client) UpdateJournalContext(ctx context.Context, journal Journal) error {
+	return c.put(ctx, "/journals/"+strconv.Itoa(journal.Id)+".json", journalRequest{Journal: journalUpdate{
+		Notes: journal.Notes,
+	}}, nil)
+}
+
+// SetJournalPrivate makes the notes of the journal with the given id private or public without changing the notes.
+// This requires Redmine 5.0 or later.
+func (c *Client) SetJournalPrivate(id int, private bool) error {
+	return c.SetJournalPrivateContext(context.Background(), id, private)
+}
+
+// SetJournalPrivateContext is like SetJournalPrivate but binds the request to ctx.
+func (c *Client) SetJournalPrivateContext(ctx context.Context, id int, private bool) error {
+	return c.put(ctx, "/journals/"+strconv.Itoa(id)+".json", journalRequest{Journal: journalUpdate{
+		PrivateNotes: &private,
+	}}, nil)
+}
+
+func (c *Client) IssuesByQuery(queryId int) ([]Issue, error) {
+	return c.IssuesByQueryContext(context.Background(), queryId)
+}
+
+func (c *Client) IssuesByQueryContext(ctx context.Context, queryId int) ([]Issue, error) {
+	return getIssues(ctx, c, "query_id="+strconv.Itoa(queryId))
 }
 
 // IssuesByFilter filters issues applying the f criteria
 func (c *Client) IssuesByFilter(f *IssueFilter) ([]Issue, error) {
-	issues, err := getIssues(c, "/issues.json?key="+c.apikey+c.getPaginationClause()+getIssueFilterClause(f))
+	return c.IssuesByFilterContext(context.Background(), f)
+}
+
+// IssuesByFilterContext is like IssuesByFilter but binds the request to ctx.
+func (c *Client) IssuesByFilterContext(ctx context.Context, f *IssueFilter) ([]Issue, error) {
+	return getIssues(ctx, c, getIssueFilterClause(f))
+}
+
+// IssuesByFilterParallel is like IssuesByFilter but fetches the pages after the first one with up to workers
+// concurrent requests. The issues are returned in the same order as by IssuesByFilter. The page size is configured
+// with WithPageSize; Redmine allows up to 100 issues per page by default.
+func (c *Client) IssuesByFilterParallel(f *IssueFilter, workers int) ([]Issue, error) {
+	return c.IssuesByFilterParallelContext(context.Background(), f, workers)
+}
+
+// IssuesByFilterParallelContext is like IssuesByFilterParallel but binds the requests to ctx. The first failing
+// request cancels all others.
+func (c *Client) IssuesByFilterParallelContext(ctx context.Context, f *IssueFilter, workers int) ([]Issue, error) {
+	pages, err := fetchPagesInParallel(ctx, workers, c.Limit, func(ctx context.Context, pagination ...string) (interface{}, int, int, error) {
+		var r issuesResult
+		err := c.get(ctx, c.pathWithParameters("/issues.json", append(pagination, getIssueFilterClause(f))...), &r)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		return r.Issues, len(r.Issues), int(r.TotalCount), nil
+	})
 	if err != nil {
 		return nil, err
 	}
+
+	var issues []Issue
+	for _, page := range pages {
+		issues = append(issues, page.([]Issue)...)
+	}
 	return issues, nil
 }
 
 func (c *Client) Issues() ([]Issue, error) {
-	issues, err := getIssues(c, "/issues.json?key="+c.apikey+c.getPaginationClause())
+	return c.IssuesContext(context.Background())
+}
 
-	if err != nil {
-		return nil, err
-	}
+func (c *Client) IssuesContext(ctx context.Context) ([]Issue, error) {
+	return getIssues(ctx, c)
+}
 
-	return issues, nil
+// IssueIterator iterates over the issues of a list request, see Paginator.
+type IssueIterator struct {
+	*Paginator
+	page []Issue
 }
 
-func (c *Client) CreateIssue(issue Issue) (*Issue, error) {
-	var ir issueRequest
-	ir.Issue = issue
-	s, err := json.Marshal(ir)
-	if err != nil {
-		return nil, err
-	}
-	req, err := http.NewRequest("POST", c.endpoint+"/issues.json?key="+c.apikey, strings.NewReader(string(s)))
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	res, err := c.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	decoder := json.NewDecoder(res.Body)
-	var r issueRequest
-	if !isHTTPStatusSuccessful(res.StatusCode, []int{http.StatusCreated}) {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
+// Item returns the current issue.
+func (it *IssueIterator) Item() Issue {
+	return it.page[it.index]
+}
+
+// IterateIssues returns an iterator over all issues matching filter, which may be nil. Every request fetches up to
+// pageSize issues; values below 1 use Redmine's default page size.
+func (c *Client) IterateIssues(ctx context.Context, filter *IssueFilter, pageSize int) *IssueIterator {
+	return c.iterateIssues(ctx, pageSize, getIssueFilterClause(filter))
+}
+
+func (c *Client) iterateIssues(ctx context.Context, pageSize int, parameters ...string) *IssueIterator {
+	it := &IssueIterator{}
+	it.Paginator = newPaginator(ctx, pageSize, func(ctx context.Context, pagination ...string) (int, int, error) {
+		var r issuesResult
+		err := c.get(ctx, c.pathWithParameters("/issues.json", append(pagination, parameters...)...), &r)
+		if err != nil {
+			return 0, 0, err
 		}
-	} else {
-		err = decoder.Decode(&r)
-	}
+		it.page = r.Issues
+		return len(r.Issues), int(r.TotalCount), nil
+	})
+	return it
+}
+
+func (c *Client) CreateIssue(issue Issue) (*Issue, error) {
+	return c.CreateIssueContext(context.Background(), issue)
+}
+
+func (c *Client) CreateIssueContext(ctx context.Context, issue Issue) (*Issue, error) {
+	var r issueResult
+	err := c.post(ctx, "/issues.json", issueRequest{Issue: issue}, &r)
 	if err != nil {
 		return nil, err
 	}
@@ -170,65 +338,19 @@ func (c *Client) CreateIssue(issue Issue) (*Issue, error) {
 }
 
 func (c *Client) UpdateIssue(issue Issue) error {
-	var ir issueRequest
-	ir.Issue = issue
-	s, err := json.Marshal(ir)
-	if err != nil {
-		return err
-	}
-	req, err := http.NewRequest("PUT", c.endpoint+"/issues/"+strconv.Itoa(issue.Id)+".json?key="+c.apikey, strings.NewReader(string(s)))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	res, err := c.Do(req)
-	if err != nil {
-		return err
-	}
-	defer res.Body.Close()
+	return c.UpdateIssueContext(context.Background(), issue)
+}
 
-	if res.StatusCode == http.StatusNotFound {
-		return fmt.Errorf("could not update issue (id: %d) because it was not found", issue.Id)
-	}
-	if !isHTTPStatusSuccessful(res.StatusCode, []int{http.StatusOK, http.StatusNoContent}) {
-		decoder := json.NewDecoder(res.Body)
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	}
-	if err != nil {
-		return err
-	}
-	return err
+func (c *Client) UpdateIssueContext(ctx context.Context, issue Issue) error {
+	return c.put(ctx, "/issues/"+strconv.Itoa(issue.Id)+".json", issueRequest{Issue: issue}, nil)
 }
 
 func (c *Client) DeleteIssue(id int) error {
-	req, err := http.NewRequest("DELETE", c.endpoint+"/issues/"+strconv.Itoa(id)+".json?key="+c.apikey, strings.NewReader(""))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	res, err := c.Do(req)
-	if err != nil {
-		return err
-	}
-	defer res.Body.Close()
-
-	if res.StatusCode == http.StatusNotFound {
-		return fmt.Errorf("could not delete issue (id: %d) because it was not found", id)
-	}
+	return c.DeleteIssueContext(context.Background(), id)
+}
 
-	decoder := json.NewDecoder(res.Body)
-	if !isHTTPStatusSuccessful(res.StatusCode, []int{http.StatusOK, http.StatusNoContent}) {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	}
-	return err
+func (c *Client) DeleteIssueContext(ctx context.Context, id int) error {
+	return c.delete(ctx, "/issues/"+strconv.Itoa(id)+".json")
 }
 
 func (issue *Issue) GetTitle() string {
@@ -306,83 +428,32 @@ func mapConcat(m map[string]string, delimiter string) string {
 	return strings.Join(args, delimiter)
 }
 
-func getOneIssue(c *Client, id int, args map[string]string) (*Issue, error) {
-	url := c.endpoint + "/issues/" + strconv.Itoa(id) + ".json?key=" + c.apikey
-
+func getOneIssue(ctx context.Context, c *Client, id int, args map[string]string) (*Issue, error) {
+	path := "/issues/" + strconv.Itoa(id) + ".json"
 	if args != nil {
-		url += "&" + mapConcat(args, "&")
+		path = c.pathWithParameters(path, mapConcat(args, "&"))
 	}
 
-	res, err := c.Get(url)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	if res.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("issue (id: %d) was not found", id)
-	}
-
-	decoder := json.NewDecoder(res.Body)
-	var r issueRequest
-	if res.StatusCode != 200 {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	} else {
-		err = decoder.Decode(&r)
-	}
+	var r issueResult
+	err := c.get(ctx, path, &r)
 	if err != nil {
 		return nil, err
 	}
 	return &r.Issue, nil
 }
 
-func getIssue(c *Client, url string, offset int) (*issuesResult, error) {
-	res, err := c.Get(c.endpoint + url + "&offset=" + strconv.Itoa(offset))
-
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	decoder := json.NewDecoder(res.Body)
-	var r issuesResult
-	if res.StatusCode != 200 {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	} else {
-		err = decoder.Decode(&r)
-	}
-	if err != nil {
-		return nil, err
+func getIssues(ctx context.Context, c *Client, parameters ...string) ([]Issue, error) {
+	it := c.iterateIssues(ctx, c.Limit, parameters...)
+	if c.Offset > 0 {
+		it.offset = c.Offset
 	}
 
-	return &r, nil
-}
-
-func getIssues(c *Client, url string) ([]Issue, error) {
-	completed := false
 	var issues []Issue
-
-	for completed == false {
-		r, err := getIssue(c, url, len(issues))
-
-		if err != nil {
-			return nil, err
-		}
-
-		if r.TotalCount == uint(len(issues)) {
-			completed = true
-		}
-
-		issues = append(issues, r.Issues...)
+	for it.Next() {
+		issues = append(issues, it.Item())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
 	}
-
 	return issues, nil
 }
@@ -1,11 +1,8 @@
 package redmine
 
 import (
-	"encoding/json"
-	"errors"
-	"net/http"
+	"context"
 	"strconv"
-	"strings"
 )
 
 type issueCategoriesResult struct {
@@ -29,26 +26,12 @@ type IssueCategory struct {
 }
 
 func (c *Client) IssueCategories(projectId int) ([]IssueCategory, error) {
-	res, err := c.Get(c.endpoint + "/projects/" + strconv.Itoa(projectId) + "/issue_categories.json?key=" + c.apikey + c.getPaginationClause())
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
+	return c.IssueCategoriesContext(context.Background(), projectId)
+}
 
-	decoder := json.NewDecoder(res.Body)
+func (c *Client) IssueCategoriesContext(ctx context.Context, projectId int) ([]IssueCategory, error) {
 	var r issueCategoriesResult
-	if res.StatusCode == 404 {
-		return nil, errors.New("Not Found")
-	}
-	if res.StatusCode != 200 {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	} else {
-		err = decoder.Decode(&r)
-	}
+	err := c.get(ctx, c.pathWithParameters("/projects/"+strconv.Itoa(projectId)+"/issue_categories.json", c.getPaginationClause()), &r)
 	if err != nil {
 		return nil, err
 	}
@@ -56,26 +39,12 @@ func (c *Client) IssueCategories(projectId int) ([]IssueCategory, error) {
 }
 
 func (c *Client) IssueCategory(id int) (*IssueCategory, error) {
-	res, err := c.Get(c.endpoint + "/issue_categories/" + strconv.Itoa(id) + ".json?key=" + c.apikey)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
+	return c.IssueCategoryContext(context.Background(), id)
+}
 
-	decoder := json.NewDecoder(res.Body)
+func (c *Client) IssueCategoryContext(ctx context.Context, id int) (*IssueCategory, error) {
 	var r issueCategoryResult
-	if res.StatusCode == 404 {
-		return nil, errors.New("Not Found")
-	}
-	if res.StatusCode != 200 {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	} else {
-		err = decoder.Decode(&r)
-	}
+	err := c.get(ctx, "/issue_categories/"+strconv.Itoa(id)+".json", &r)
 	if err != nil {
 		return nil, err
 	}
@@ -83,34 +52,12 @@ func (c *Client) IssueCategory(id int) (*IssueCategory, error) {
 }
 
 func (c *Client) CreateIssueCategory(issueCategory IssueCategory) (*IssueCategory, error) {
-	var ir issueCategoryRequest
-	ir.IssueCategory = issueCategory
-	s, err := json.Marshal(ir)
-	if err != nil {
-		return nil, err
-	}
-	req, err := http.NewRequest("POST", c.endpoint+"/issue_categories.json?key="+c.apikey, strings.NewReader(string(s)))
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	res, err := c.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
+	return c.CreateIssueCategoryContext(context.Background(), issueCategory)
+}
 
-	decoder := json.NewDecoder(res.Body)
+func (c *Client) CreateIssueCategoryContext(ctx context.Context, issueCategory IssueCategory) (*IssueCategory, error) {
 	var r issueCategoryResult
-	if res.StatusCode != 201 {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	} else {
-		err = decoder.Decode(&r)
-	}
+	err := c.post(ctx, "/issue_categories.json", issueCategoryRequest{IssueCategory: issueCategory}, &r)
 	if err != nil {
 		return nil, err
 	}
@@ -118,63 +65,17 @@ func (c *Client) CreateIssueCategory(issueCategory IssueCategory) (*IssueCategor
 }
 
 func (c *Client) UpdateIssueCategory(issueCategory IssueCategory) error {
-	var ir issueCategoryRequest
-	ir.IssueCategory = issueCategory
-	s, err := json.Marshal(ir)
-	if err != nil {
-		return err
-	}
-	req, err := http.NewRequest("PUT", c.endpoint+"/issue_categories/"+strconv.Itoa(issueCategory.Id)+".json?key="+c.apikey, strings.NewReader(string(s)))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	res, err := c.Do(req)
-	if err != nil {
-		return err
-	}
-	defer res.Body.Close()
+	return c.UpdateIssueCategoryContext(context.Background(), issueCategory)
+}
 
-	if res.StatusCode == 404 {
-		return errors.New("Not Found")
-	}
-	if res.StatusCode != 200 {
-		decoder := json.NewDecoder(res.Body)
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	}
-	if err != nil {
-		return err
-	}
-	return err
+func (c *Client) UpdateIssueCategoryContext(ctx context.Context, issueCategory IssueCategory) error {
+	return c.put(ctx, "/issue_categories/"+strconv.Itoa(issueCategory.Id)+".json", issueCategoryRequest{IssueCategory: issueCategory}, nil)
 }
 
 func (c *Client) DeleteIssueCategory(id int) error {
-	req, err := http.NewRequest("DELETE", c.endpoint+"/issue_categories/"+strconv.Itoa(id)+".json?key="+c.apikey, strings.NewReader(""))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	res, err := c.Do(req)
-	if err != nil {
-		return err
-	}
-	defer res.Body.Close()
-
-	if res.StatusCode == 404 {
-		return errors.New("Not Found")
-	}
+	return c.DeleteIssueCategoryContext(context.Background(), id)
+}
 
-	decoder := json.NewDecoder(res.Body)
-	if res.StatusCode != 200 {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	}
-	return err
+func (c *Client) DeleteIssueCategoryContext(ctx context.Context, id int) error {
+	return c.delete(ctx, "/issue_categories/"+strconv.Itoa(id)+".json")
 }
@@ -1,11 +1,7 @@
 package redmine
 
 import (
-	"encoding/json"
-	"errors"
-	"fmt"
-	"net/http"
-	"strings"
+	"context"
 )
 
 type customFieldsResult struct {
@@ -14,36 +10,13 @@ type customFieldsResult struct {
 
 // CustomFields consulta los campos personalizados
 func (c *Client) CustomFields() ([]CustomField, error) {
-	req, err := http.NewRequest(
-		"GET",
-		fmt.Sprintf("%s/custom_fields.json?%s",
-			c.endpoint,
-			c.getPaginationClause()),
-		nil)
-	if err != nil {
-		return nil, err
-	}
-
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("X-Redmine-API-Key", c.apikey)
-
-	res, err := c.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
+	return c.CustomFieldsContext(context.Background())
+}
 
-	decoder := json.NewDecoder(res.Body)
+// CustomFieldsContext is like CustomFields but binds the request to ctx.
+func (c *Client) CustomFieldsContext(ctx context.Context) ([]CustomField, error) {
 	var r customFieldsResult
-	if res.StatusCode != 200 {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	} else {
-		err = decoder.Decode(&r)
-	}
+	err := c.get(ctx, c.pathWithParameters("/custom_fields.json", c.getPaginationClause()), &r)
 	if err != nil {
 		return nil, err
 	}
@@ -1,9 +1,7 @@
 package redmine
 
 import (
-	"encoding/json"
-	"errors"
-	"strings"
+	"context"
 )
 
 type issuePrioritiesResult struct {
@@ -17,23 +15,12 @@ type IssuePriority struct {
 }
 
 func (c *Client) IssuePriorities() ([]IssuePriority, error) {
-	res, err := c.Get(c.endpoint + "/enumerations/issue_priorities.json?key=" + c.apikey + c.getPaginationClause())
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
+	return c.IssuePrioritiesContext(context.Background())
+}
 
-	decoder := json.NewDecoder(res.Body)
+func (c *Client) IssuePrioritiesContext(ctx context.Context) ([]IssuePriority, error) {
 	var r issuePrioritiesResult
-	if res.StatusCode != 200 {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	} else {
-		err = decoder.Decode(&r)
-	}
+	err := c.get(ctx, c.pathWithParameters("/enumerations/issue_priorities.json", c.getPaginationClause()), &r)
 	if err != nil {
 		return nil, err
 	}
@@ -1,11 +1,9 @@
 package redmine
 
 import (
+	"context"
 	"encoding/json"
-	"errors"
-	"net/http"
 	"strconv"
-	"strings"
 )
 
 type issueRelationsResult struct {
@@ -28,27 +26,53 @@ type IssueRelation struct {
 	Delay        string `json:"delay"`
 }
 
-func (c *Client) IssueRelations(issueId int) ([]IssueRelation, error) {
-	res, err := c.Get(c.endpoint + "/issue/" + strconv.Itoa(issueId) + "/relations.json?key=" + c.apikey + c.getPaginationClause())
-	if err != nil {
-		return nil, err
+// UnmarshalJSON accepts numbers as well as strings for IssueId, IssueToId and Delay because Redmine returns them as
+// numbers.
+func (r *IssueRelation) UnmarshalJSON(data []byte) error {
+	type issueRelation IssueRelation
+	var v struct {
+		issueRelation
+		IssueId   numberOrString `json:"issue_id"`
+		IssueToId numberOrString `json:"issue_to_id"`
+		Delay     numberOrString `json:"delay"`
+	}
+	if err := json.Unmarshal(data, &v); err != nil {
+		return err
 	}
-	defer res.Body.Close()
 
-	decoder := json.NewDecoder(res.Body)
-	var r issueRelationsResult
-	if res.StatusCode == 404 {
-		return nil, errors.New("Not Found")
+	*r = IssueRelation(v.issueRelation)
+	r.IssueId = string(v.IssueId)
+	r.IssueToId = string(v.IssueToId)
+	r.Delay = string(v.Delay)
+	return nil
+}
+
+// numberOrString holds a JSON number or string as string.
+type numberOrString string
+
+func (s *numberOrString) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
 	}
-	if res.StatusCode != 200 {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	} else {
-		err = decoder.Decode(&r)
+	if len(data) > 0 && data[0] == '"' {
+		var value string
+		err := json.Unmarshal(data, &value)
+		*s = numberOrString(value)
+		return err
 	}
+	var number json.Number
+	err := json.Unmarshal(data, &number)
+	*s = numberOrString(number)
+	return err
+}
+
+func (c *Client) IssueRelations(issueId int) ([]IssueRelation, error) {
+	return c.IssueRelationsContext(context.Background(), issueId)
+}
+
+func (c *Client) IssueRelationsContext(ctx context.Context, issueId int) ([]IssueRelation, error) {
+	var r issueRelationsResult
+	err := c.get(ctx, c.pathWithParameters("/issue/"+strconv.Itoa(issueId)+"/relations.json", c.getPaginationClause()), &r)
 	if err != nil {
 		return nil, err
 	}
@@ -56,26 +80,12 @@ func (c *Client) IssueRelations(issueId int) ([]IssueRelation, error) {
 }
 
 func (c *Client) IssueRelation(id int) (*IssueRelation, error) {
-	res, err := c.Get(c.endpoint + "/relations/" + strconv.Itoa(id) + ".json?key=" + c.apikey)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
+	return c.IssueRelationContext(context.Background(), id)
+}
 
-	decoder := json.NewDecoder(res.Body)
+func (c *Client) IssueRelationContext(ctx context.Context, id int) (*IssueRelation, error) {
 	var r issueRelationResult
-	if res.StatusCode == 404 {
-		return nil, errors.New("Not Found")
-	}
-	if res.StatusCode != 200 {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	} else {
-		err = decoder.Decode(&r)
-	}
+	err := c.get(ctx, "/relations/"+strconv.Itoa(id)+".json", &r)
 	if err != nil {
 		return nil, err
 	}
@@ -83,34 +93,12 @@ func (c *Client) IssueRelation(id int) (*IssueRelation, error) {
 }
 
 func (c *Client) CreateIssueRelation(issueRelation IssueRelation) (*IssueRelation, error) {
-	var ir issueRelationRequest
-	ir.IssueRelation = issueRelation
-	s, err := json.Marshal(ir)
-	if err != nil {
-		return nil, err
-	}
-	req, err := http.NewRequest("POST", c.endpoint+"/relations.json?key="+c.apikey, strings.NewReader(string(s)))
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	res, err := c.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
+	return c.CreateIssueRelationContext(context.Background(), issueRelation)
+}
 
-	decoder := json.NewDecoder(res.Body)
+func (c *Client) CreateIssueRelationContext(ctx context.Context, issueRelation IssueRelation) (*IssueRelation, error) {
 	var r issueRelationResult
-	if res.StatusCode != 201 {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	} else {
-		err = decoder.Decode(&r)
-	}
+	err := c.post(ctx, "/relations.json", issueRelationRequest{IssueRelation: issueRelation}, &r)
 	if err != nil {
 		return nil, err
 	}
@@ -118,63 +106,17 @@ func (c *Client) CreateIssueRelation(issueRelation IssueRelation) (*IssueRelatio
 }
 
 func (c *Client) UpdateIssueRelation(issueRelation IssueRelation) error {
-	var ir issueRelationRequest
-	ir.IssueRelation = issueRelation
-	s, err := json.Marshal(ir)
-	if err != nil {
-		return err
-	}
-	req, err := http.NewRequest("PUT", c.endpoint+"/relations/"+strconv.Itoa(issueRelation.Id)+".json?key="+c.apikey, strings.NewReader(string(s)))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	res, err := c.Do(req)
-	if err != nil {
-		return err
-	}
-	defer res.Body.Close()
+	return c.UpdateIssueRelationContext(context.Background(), issueRelation)
+}
 
-	if res.StatusCode == 404 {
-		return errors.New("Not Found")
-	}
-	if res.StatusCode != 200 {
-		decoder := json.NewDecoder(res.Body)
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	}
-	if err != nil {
-		return err
-	}
-	return err
+func (c *Client) UpdateIssueRelationContext(ctx context.Context, issueRelation IssueRelation) error {
+	return c.put(ctx, "/relations/"+strconv.Itoa(issueRelation.Id)+".json", issueRelationRequest{IssueRelation: issueRelation}, nil)
 }
 
 func (c *Client) DeleteIssueRelation(id int) error {
-	req, err := http.NewRequest("DELETE", c.endpoint+"/relations/"+strconv.Itoa(id)+".json?key="+c.apikey, strings.NewReader(""))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	res, err := c.Do(req)
-	if err != nil {
-		return err
-	}
-	defer res.Body.Close()
-
-	if res.StatusCode == 404 {
-		return errors.New("Not Found")
-	}
+	return c.DeleteIssueRelationContext(context.Background(), id)
+}
 
-	decoder := json.NewDecoder(res.Body)
-	if res.StatusCode != 200 {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	}
-	return err
+func (c *Client) DeleteIssueRelationContext(ctx context.Context, id int) error {
+	return c.delete(ctx, "/relations/"+strconv.Itoa(id)+".json")
 }
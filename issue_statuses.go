@@ -1,9 +1,7 @@
 package redmine
 
 import (
-	"encoding/json"
-	"errors"
-	"strings"
+	"context"
 )
 
 type issueStatusesResult struct {
@@ -18,23 +16,12 @@ type IssueStatus struct {
 }
 
 func (c *Client) IssueStatuses() ([]IssueStatus, error) {
-	res, err := c.Get(c.endpoint + "/issue_statuses.json?key=" + c.apikey + c.getPaginationClause())
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
+	return c.IssueStatusesContext(context.Background())
+}
 
-	decoder := json.NewDecoder(res.Body)
+func (c *Client) IssueStatusesContext(ctx context.Context) ([]IssueStatus, error) {
 	var r issueStatusesResult
-	if res.StatusCode != 200 {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	} else {
-		err = decoder.Decode(&r)
-	}
+	err := c.get(ctx, c.pathWithParameters("/issue_statuses.json", c.getPaginationClause()), &r)
 	if err != nil {
 		return nil, err
 	}
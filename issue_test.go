@@ -1,9 +1,13 @@
 package redmine
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -53,9 +57,9 @@ func Test_getOneIssue(t *testing.T) {
 		}))
 		defer ts.Close()
 
-		sut := NewClient(ts.URL, "apiKey")
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
 
-		actual, err := getOneIssue(sut, 1, nil)
+		actual, err := getOneIssue(context.Background(), sut, 1, nil)
 
 		require.NoError(t, err)
 		assert.Equal(t, 1, actual.Id)
@@ -66,11 +70,11 @@ func Test_getOneIssue(t *testing.T) {
 		assert.Equal(t, 0, actual.ParentId)
 		assert.Equal(t, 0, actual.StatusId)
 		assert.Equal(t, 0, actual.PriorityId)
-		assert.Equal(t, "2021-02-23T14:20:48Z", actual.CreatedOn)
-		assert.Equal(t, "2021-02-23T14:39:02Z", actual.UpdatedOn)
-		assert.Equal(t, "", actual.StartDate)
-		assert.Equal(t, "", actual.DueDate)
-		assert.Equal(t, "", actual.ClosedOn)
+		assert.Equal(t, "2021-02-23T14:20:48Z", actual.CreatedOn.String())
+		assert.Equal(t, "2021-02-23T14:39:02Z", actual.UpdatedOn.String())
+		assert.Equal(t, "", actual.StartDate.String())
+		assert.Equal(t, "", actual.DueDate.String())
+		assert.Equal(t, "", actual.ClosedOn.String())
 
 		expectedProject := IdName{Id: 1, Name: "example project1"}
 		assert.Equal(t, expectedProject, *actual.Project)
@@ -85,3 +89,262 @@ func Test_getOneIssue(t *testing.T) {
 		assert.Equal(t, expectedAuthor, *actual.Author)
 	})
 }
+
+func TestClient_IssuesByFilterContext(t *testing.T) {
+	t.Run("should stop paginating when the context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		requests := 0
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			cancel()
+			_, _ = fmt.Fprintln(w, `{"issues": [{"id": 1}], "total_count": 10, "offset": 0, "limit": 1}`)
+		}))
+		defer ts.Close()
+
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		actual, err := sut.IssuesByFilterContext(ctx, nil)
+
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, context.Canceled))
+		assert.Nil(t, actual)
+		assert.Equal(t, 1, requests)
+	})
+}
+
+func TestClient_Issues(t *testing.T) {
+	t.Run("should stop when the server returns fewer issues than announced", func(t *testing.T) {
+		requests := 0
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			assert.Equal(t, "/issues.json", r.URL.Path)
+			if r.URL.Query().Get("offset") == "0" {
+				_, _ = fmt.Fprintln(w, `{"issues": [{"id": 1}, {"id": 2}], "total_count": 3}`)
+				return
+			}
+			_, _ = fmt.Fprintln(w, `{"issues": [], "total_count": 3}`)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		actual, err := sut.IssuesContext(context.Background())
+
+		require.NoError(t, err)
+		assert.Len(t, actual, 2)
+		assert.Equal(t, 2, requests)
+	})
+
+	t.Run("should request pages with the page size of the client", func(t *testing.T) {
+		var queries []string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			queries = append(queries, r.URL.RawQuery)
+			if r.URL.Query().Get("offset") == "0" {
+				_, _ = fmt.Fprintln(w, `{"issues": [{"id": 1}], "total_count": 2}`)
+				return
+			}
+			_, _ = fmt.Fprintln(w, `{"issues": [{"id": 2}], "total_count": 2}`)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"), WithPageSize(1))
+
+		actual, err := sut.IssuesOf(4)
+
+		require.NoError(t, err)
+		assert.Len(t, actual, 2)
+		assert.Equal(t, []string{"offset=0&limit=1&project_id=4", "offset=1&limit=1&project_id=4"}, queries)
+	})
+}
+
+func TestClient_IssueWithIncludes(t *testing.T) {
+	t.Run("should request all includes and parse the additional fields", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/issues/1.json", r.URL.Path)
+			assert.Equal(t, "children,relations,changesets,allowed_statuses", r.URL.Query().Get("include"))
+			_, _ = fmt.Fprintln(w, `{
+  "issue": {
+    "id": 1,
+    "subject": "Something should be done",
+    "children": [
+      {"id": 2, "tracker": {"id": 1, "name": "Bug"}, "subject": "Subtask", "children": [{"id": 3, "tracker": {"id": 1, "name": "Bug"}, "subject": "Subsubtask"}]}
+    ],
+    "relations": [
+      {"id": 7, "issue_id": 1, "issue_to_id": 4, "relation_type": "precedes", "delay": 2},
+      {"id": 8, "issue_id": 5, "issue_to_id": 1, "relation_type": "relates", "delay": null}
+    ],
+    "changesets": [
+      {"revision": "6d4e1a0", "user": {"id": 1, "name": "Redmine Admin"}, "comments": "Fix #1", "committed_on": "2021-02-24T08:00:00Z"}
+    ],
+    "allowed_statuses": [
+      {"id": 1, "name": "New", "is_closed": false},
+      {"id": 5, "name": "Closed", "is_closed": true}
+    ]
+  }
+}`)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		actual, err := sut.IssueWithIncludes(1, IssueIncludeChildren, IssueIncludeRelations, IssueIncludeChangesets, IssueIncludeAllowedStatuses)
+
+		require.NoError(t, err)
+		require.Len(t, actual.Children, 1)
+		assert.Equal(t, "Subtask", actual.Children[0].Subject)
+		assert.Equal(t, []IssueChild{{Id: 3, Tracker: &IdName{Id: 1, Name: "Bug"}, Subject: "Subsubtask"}}, actual.Children[0].Children)
+		assert.Equal(t, []IssueRelation{
+			{Id: 7, IssueId: "1", IssueToId: "4", RelationType: "precedes", Delay: "2"},
+			{Id: 8, IssueId: "5", IssueToId: "1", RelationType: "relates"},
+		}, actual.Relations)
+		require.Len(t, actual.Changesets, 1)
+		assert.Equal(t, "6d4e1a0", actual.Changesets[0].Revision)
+		assert.Equal(t, "2021-02-24T08:00:00Z", actual.Changesets[0].CommittedOn.String())
+		assert.Equal(t, []IssueStatus{{Id: 1, Name: "New"}, {Id: 5, Name: "Closed", IsClosed: true}}, actual.AllowedStatuses)
+	})
+
+	t.Run("should not send an include parameter without includes", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "", r.URL.RawQuery)
+			_, _ = fmt.Fprintln(w, `{"issue": {"id": 1}}`)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		_, err := sut.IssueWithIncludes(1)
+
+		require.NoError(t, err)
+	})
+}
+
+func TestIssueRelation_UnmarshalJSON(t *testing.T) {
+	t.Run("should accept strings", func(t *testing.T) {
+		var actual IssueRelation
+
+		err := json.Unmarshal([]byte(`{"id": 7, "issue_id": "1", "issue_to_id": "4", "relation_type": "blocks", "delay": ""}`), &actual)
+
+		require.NoError(t, err)
+		assert.Equal(t, IssueRelation{Id: 7, IssueId: "1", IssueToId: "4", RelationType: "blocks"}, actual)
+	})
+}
+
+func TestClient_IssueJournals(t *testing.T) {
+	t.Run("should return the history with typed property kinds", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/issues/1.json", r.URL.Path)
+			assert.Equal(t, "journals", r.URL.Query().Get("include"))
+			_, _ = fmt.Fprintln(w, `{
+  "issue": {
+    "id": 1,
+    "journals": [
+      {
+        "id": 10,
+        "user": {"id": 1, "name": "Redmine Admin"},
+        "notes": "Customer called again",
+        "private_notes": true,
+        "created_on": "2021-02-24T08:00:00Z",
+        "details": [
+          {"property": "attr", "name": "status_id", "old_value": "1", "new_value": "2"},
+          {"property": "cf", "name": "3", "old_value": null, "new_value": "high"},
+          {"property": "attachment", "name": "6", "new_value": "screenshot.png"},
+          {"property": "relation", "name": "relates", "new_value": "4"}
+        ]
+      }
+    ]
+  }
+}`)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		actual, err := sut.IssueJournals(1)
+
+		require.NoError(t, err)
+		require.Len(t, actual, 1)
+		assert.True(t, actual[0].PrivateNotes)
+		assert.Equal(t, "Customer called again", actual[0].Notes)
+		assert.Equal(t, []JournalDetails{
+			{Property: JournalPropertyAttr, Name: "status_id", OldValue: "1", NewValue: "2"},
+			{Property: JournalPropertyCustomField, Name: "3", NewValue: "high"},
+			{Property: JournalPropertyAttachment, Name: "6", NewValue: "screenshot.png"},
+			{Property: JournalPropertyRelation, Name: "relates", NewValue: "4"},
+		}, actual[0].Details)
+	})
+}
+
+func TestClient_UpdateJournal(t *testing.T) {
+	t.Run("should put the notes only and keep their visibility", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPut, r.Method)
+			assert.Equal(t, "/journals/10.json", r.URL.Path)
+			body, _ := ioutil.ReadAll(r.Body)
+			assert.JSONEq(t, `{"journal": {"notes": "Customer called twice"}}`, string(body))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		err := sut.UpdateJournal(Journal{Id: 10, Notes: "Customer called twice", PrivateNotes: true, User: &IdName{Id: 1}})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should not send empty notes", func(t *testing.T) {
+		var body struct {
+			Journal map[string]interface{} `json:"journal"`
+		}
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		err := sut.UpdateJournal(Journal{Id: 5})
+
+		require.NoError(t, err)
+		assert.NotContains(t, body.Journal, "notes")
+		assert.NotContains(t, body.Journal, "private_notes")
+	})
+}
+
+func TestClient_SetJournalPrivate(t *testing.T) {
+	for _, private := range []bool{true, false} {
+		t.Run(fmt.Sprintf("should put private_notes %t only", private), func(t *testing.T) {
+			var body struct {
+				Journal map[string]interface{} `json:"journal"`
+			}
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPut, r.Method)
+				assert.Equal(t, "/journals/5.json", r.URL.Path)
+				require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer ts.Close()
+			sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+			err := sut.SetJournalPrivate(5, private)
+
+			require.NoError(t, err)
+			assert.Equal(t, map[string]interface{}{"private_notes": private}, body.Journal)
+		})
+	}
+}
+
+func TestClient_UpdateIssue(t *testing.T) {
+	t.Run("should send private notes", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/issues/1.json", r.URL.Path)
+			var body struct {
+				Issue map[string]interface{} `json:"issue"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, "Internal note", body.Issue["notes"])
+			assert.Equal(t, true, body.Issue["private_notes"])
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		err := sut.UpdateIssue(Issue{Id: 1, Notes: "Internal note", PrivateNotes: true})
+
+		require.NoError(t, err)
+	})
+}
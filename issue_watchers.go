@@ -0,0 +1,26 @@
+package redmine
+
+import (
+	"context"
+	"strconv"
+)
+
+// AddWatcher adds the user with the id userId to the watchers of the issue with the id issueId.
+func (c *Client) AddWatcher(issueId int, userId int) error {
+	return c.AddWatcherContext(context.Background(), issueId, userId)
+}
+
+// AddWatcherContext is like AddWatcher but binds the request to ctx.
+func (c *Client) AddWatcherContext(ctx context.Context, issueId int, userId int) error {
+	return c.post(ctx, "/issues/"+strconv.Itoa(issueId)+"/watchers.json", userIdRequest{UserId: userId}, nil)
+}
+
+// RemoveWatcher removes the user with the id userId from the watchers of the issue with the id issueId.
+func (c *Client) RemoveWatcher(issueId int, userId int) error {
+	return c.RemoveWatcherContext(context.Background(), issueId, userId)
+}
+
+// RemoveWatcherContext is like RemoveWatcher but binds the request to ctx.
+func (c *Client) RemoveWatcherContext(ctx context.Context, issueId int, userId int) error {
+	return c.delete(ctx, "/issues/"+strconv.Itoa(issueId)+"/watchers/"+strconv.Itoa(userId)+".json")
+}
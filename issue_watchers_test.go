@@ -0,0 +1,82 @@
+package redmine
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_AddWatcher(t *testing.T) {
+	t.Run("should post the user id", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "/issues/42/watchers.json", r.URL.Path)
+			body, _ := ioutil.ReadAll(r.Body)
+			assert.JSONEq(t, `{"user_id": 5}`, string(body))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		err := sut.AddWatcher(42, 5)
+
+		require.NoError(t, err)
+	})
+}
+
+func TestClient_RemoveWatcher(t *testing.T) {
+	t.Run("should delete the watcher", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodDelete, r.Method)
+			assert.Equal(t, "/issues/42/watchers/5.json", r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		err := sut.RemoveWatcher(42, 5)
+
+		require.NoError(t, err)
+	})
+}
+
+func TestClient_IssueWatchers(t *testing.T) {
+	t.Run("should parse watchers", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "watchers", r.URL.Query().Get("include"))
+			_, _ = fmt.Fprintln(w, `{"issue": {"id": 42, "watchers": [{"id": 5, "name": "John Smith"}]}}`)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		actual, err := sut.IssueWithArgs(42, map[string]string{"include": "watchers"})
+
+		require.NoError(t, err)
+		assert.Equal(t, []IdName{{Id: 5, Name: "John Smith"}}, actual.Watchers)
+	})
+
+	t.Run("should send watcher user ids when creating an issue", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			var body struct {
+				Issue map[string]interface{} `json:"issue"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, []interface{}{float64(5), float64(6)}, body.Issue["watcher_user_ids"])
+			w.WriteHeader(http.StatusCreated)
+			_, _ = fmt.Fprintln(w, `{"issue": {"id": 43}}`)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		actual, err := sut.CreateIssue(Issue{ProjectId: 1, Subject: "Deploy", WatcherUserIds: []int{5, 6}})
+
+		require.NoError(t, err)
+		assert.Equal(t, 43, actual.Id)
+	})
+}
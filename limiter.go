@@ -0,0 +1,111 @@
+package redmine
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// WithRateLimit limits the client to requestsPerSecond requests per second on average while allowing bursts of up to
+// burst requests. Retries count as requests. The limit is shared by all goroutines using the client.
+func WithRateLimit(requestsPerSecond float64, burst int) Option {
+	return func(o *options) {
+		o.rateLimiter = newTokenBucket(requestsPerSecond, burst)
+	}
+}
+
+// WithMaxConcurrentRequests limits the number of calls which are in flight at the same time to maxConcurrent. Further
+// calls block until a running call completes or their context is done. The limit is shared by all goroutines using
+// the client.
+func WithMaxConcurrentRequests(maxConcurrent int) Option {
+	return func(o *options) {
+		o.maxConcurrent = maxConcurrent
+	}
+}
+
+// tokenBucket is a token bucket rate limiter. A nil *tokenBucket does not limit anything.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if rate <= 0 {
+		return nil
+	}
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+	}
+}
+
+// wait blocks until a token is available or ctx is done. Tokens are reserved in call order so that waiting callers
+// are served first come, first served.
+func (b *tokenBucket) wait(ctx context.Context) error {
+	if b == nil {
+		return nil
+	}
+
+	b.mu.Lock()
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Seconds() * b.rate
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+	b.tokens--
+	deficit := -b.tokens
+	b.mu.Unlock()
+
+	if deficit <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(time.Duration(deficit / b.rate * float64(time.Second)))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		b.mu.Lock()
+		b.tokens++
+		b.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// semaphore limits the number of concurrent calls. A nil semaphore does not limit anything.
+type semaphore chan struct{}
+
+func newSemaphore(size int) semaphore {
+	if size <= 0 {
+		return nil
+	}
+	return make(semaphore, size)
+}
+
+func (s semaphore) acquire(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	select {
+	case s <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s semaphore) release() {
+	if s != nil {
+		<-s
+	}
+}
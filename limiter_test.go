@@ -0,0 +1,76 @@
+package redmine
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func Test_tokenBucket(t *testing.T) {
+	t.Run("should allow bursts and throttle afterwards", func(t *testing.T) {
+		sut := newTokenBucket(100, 2)
+		start := time.Now()
+
+		for i := 0; i < 4; i++ {
+			require.NoError(t, sut.wait(context.Background()))
+		}
+
+		assert.True(t, time.Since(start) >= 15*time.Millisecond, "elapsed %s", time.Since(start))
+	})
+
+	t.Run("should return when the context is done", func(t *testing.T) {
+		sut := newTokenBucket(0.001, 1)
+		require.NoError(t, sut.wait(context.Background()))
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+		defer cancel()
+
+		err := sut.wait(ctx)
+
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	})
+
+	t.Run("should not limit without rate", func(t *testing.T) {
+		sut := newTokenBucket(0, 1)
+
+		assert.Nil(t, sut)
+		assert.NoError(t, sut.wait(context.Background()))
+	})
+}
+
+func TestClient_WithMaxConcurrentRequests(t *testing.T) {
+	var inFlight, maxInFlight int32
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(&inFlight, 1)
+		for {
+			observed := atomic.LoadInt32(&maxInFlight)
+			if current <= observed || atomic.CompareAndSwapInt32(&maxInFlight, observed, current) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		atomic.AddInt32(&inFlight, -1)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer ts.Close()
+
+	sut := NewClient(ts.URL, WithMaxConcurrentRequests(2))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			assert.NoError(t, sut.DeleteIssue(id))
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&maxInFlight))
+}
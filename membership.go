@@ -1,17 +1,13 @@
 package redmine
 
 import (
-	"bytes"
-	"encoding/json"
-	"errors"
-	"fmt"
-	"net/http"
+	"context"
 	"strconv"
-	"strings"
 )
 
 type membershipsResult struct {
 	Memberships []Membership `json:"memberships"`
+	TotalCount  int          `json:"total_count"`
 }
 
 type membershipResult struct {
@@ -38,94 +34,77 @@ type MembershipDTO struct {
 }
 
 func (c *Client) Memberships(projectId int) ([]Membership, error) {
-	res, err := c.Get(c.endpoint + "/projects/" + strconv.Itoa(projectId) + "/memberships.json?key=" + c.apikey + c.getPaginationClause())
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
+	return c.MembershipsContext(context.Background(), projectId)
+}
 
-	decoder := json.NewDecoder(res.Body)
+func (c *Client) MembershipsContext(ctx context.Context, projectId int) ([]Membership, error) {
 	var r membershipsResult
-	if res.StatusCode == 404 {
-		return nil, errors.New("Not Found")
-	}
-	if res.StatusCode != 200 {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	} else {
-		err = decoder.Decode(&r)
-	}
+	err := c.get(ctx, c.pathWithParameters("/projects/"+strconv.Itoa(projectId)+"/memberships.json", c.getPaginationClause()), &r)
 	if err != nil {
 		return nil, err
 	}
 	return r.Memberships, nil
 }
 
+// MembershipIterator iterates over the memberships of a list request, see Paginator.
+type MembershipIterator struct {
+	*Paginator
+	page []Membership
+}
+
+// Item returns the current membership.
+func (it *MembershipIterator) Item() Membership {
+	return it.page[it.index]
+}
+
+// IterateMemberships returns an iterator over all memberships of a project. Every request fetches up to pageSize
+// memberships; values below 1 use Redmine's default page size.
+func (c *Client) IterateMemberships(ctx context.Context, projectId int, pageSize int) *MembershipIterator {
+	it := &MembershipIterator{}
+	it.Paginator = newPaginator(ctx, pageSize, func(ctx context.Context, pagination ...string) (int, int, error) {
+		var r membershipsResult
+		err := c.get(ctx, c.pathWithParameters("/projects/"+strconv.Itoa(projectId)+"/memberships.json", pagination...), &r)
+		if err != nil {
+			return 0, 0, err
+		}
+		it.page = r.Memberships
+		return len(r.Memberships), r.TotalCount, nil
+	})
+	return it
+}
+
 func (c *Client) Membership(id int) (*Membership, error) {
-	res, err := c.Get(c.endpoint + "/memberships/" + strconv.Itoa(id) + ".json?key=" + c.apikey)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
+	return c.MembershipContext(context.Background(), id)
+}
 
-	decoder := json.NewDecoder(res.Body)
+func (c *Client) MembershipContext(ctx context.Context, id int) (*Membership, error) {
 	var r membershipResult
-	if res.StatusCode == 404 {
-		return nil, errors.New("Not Found")
-	}
-	if res.StatusCode != 200 {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	} else {
-		err = decoder.Decode(&r)
-	}
+	err := c.get(ctx, "/memberships/"+strconv.Itoa(id)+".json", &r)
 	if err != nil {
 		return nil, err
 	}
 	return &r.Membership, nil
 }
 
+// CreateMembershipByProjectID adds a member to the project with the id projectID. If the project is closed, an error
+// matching ErrProjectNotActive is returned without sending the membership. For archived projects Redmine denies
+// access, which results in an error matching ErrAuth.
 func (c *Client) CreateMembershipByProjectID(membership MembershipDTO, projectID int) (*Membership, error) {
-	project, err := c.Project(projectID)
-	if err != nil {
-		return nil, err
-	}
-	if project.Status == 5 {
-		return nil, nil
-	}
-	s, err := json.Marshal(membership)
-	if err != nil {
-		return nil, err
-	}
-	url := fmt.Sprintf(c.endpoint+"/projects/%d/memberships.json?key="+c.apikey, projectID)
-	req, err := http.NewRequest("POST", url, bytes.NewBuffer(s))
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	res, err := c.Do(req)
+	return c.CreateMembershipByProjectIDContext(context.Background(), membership, projectID)
+}
+
+// CreateMembershipByProjectIDContext is like CreateMembershipByProjectID but binds the requests to ctx.
+func (c *Client) CreateMembershipByProjectIDContext(ctx context.Context, membership MembershipDTO, projectID int) (*Membership, error) {
+	project, err := c.ProjectContext(ctx, projectID)
 	if err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
-
-	decoder := json.NewDecoder(res.Body)
-	var r membershipRequest
-	if res.StatusCode != 201 {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	} else {
-		err = decoder.Decode(&r)
+	if project.Status != 0 && project.Status != ProjectStatusActive {
+		return nil, &ProjectStatusError{ProjectId: projectID, Status: project.Status}
 	}
+
+	var r membershipResult
+	err = c.post(ctx, "/projects/"+strconv.Itoa(projectID)+"/memberships.json", membership, &r)
 	if err != nil {
 		return nil, err
 	}
@@ -133,35 +112,12 @@ func (c *Client) CreateMembershipByProjectID(membership MembershipDTO, projectID
 }
 
 func (c *Client) CreateMembership(membership Membership) (*Membership, error) {
+	return c.CreateMembershipContext(context.Background(), membership)
+}
 
-	var ir membershipRequest
-	ir.Membership = membership
-	s, err := json.Marshal(ir)
-	if err != nil {
-		return nil, err
-	}
-	req, err := http.NewRequest("POST", c.endpoint+"/memberships.json?key="+c.apikey, strings.NewReader(string(s)))
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	res, err := c.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	decoder := json.NewDecoder(res.Body)
-	var r membershipRequest
-	if res.StatusCode != 201 {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	} else {
-		err = decoder.Decode(&r)
-	}
+func (c *Client) CreateMembershipContext(ctx context.Context, membership Membership) (*Membership, error) {
+	var r membershipResult
+	err := c.post(ctx, "/memberships.json", membershipRequest{Membership: membership}, &r)
 	if err != nil {
 		return nil, err
 	}
@@ -169,63 +125,17 @@ func (c *Client) CreateMembership(membership Membership) (*Membership, error) {
 }
 
 func (c *Client) UpdateMembership(membership Membership) error {
-	var ir membershipRequest
-	ir.Membership = membership
-	s, err := json.Marshal(ir)
-	if err != nil {
-		return err
-	}
-	req, err := http.NewRequest("PUT", c.endpoint+"/memberships/"+strconv.Itoa(membership.Id)+".json?key="+c.apikey, strings.NewReader(string(s)))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	res, err := c.Do(req)
-	if err != nil {
-		return err
-	}
-	defer res.Body.Close()
+	return c.UpdateMembershipContext(context.Background(), membership)
+}
 
-	if res.StatusCode == 404 {
-		return errors.New("Not Found")
-	}
-	if res.StatusCode != 200 {
-		decoder := json.NewDecoder(res.Body)
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	}
-	if err != nil {
-		return err
-	}
-	return err
+func (c *Client) UpdateMembershipContext(ctx context.Context, membership Membership) error {
+	return c.put(ctx, "/memberships/"+strconv.Itoa(membership.Id)+".json", membershipRequest{Membership: membership}, nil)
 }
 
 func (c *Client) DeleteMembership(id int) error {
-	req, err := http.NewRequest("DELETE", c.endpoint+"/memberships/"+strconv.Itoa(id)+".json?key="+c.apikey, strings.NewReader(""))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	res, err := c.Do(req)
-	if err != nil {
-		return err
-	}
-	defer res.Body.Close()
-
-	if res.StatusCode == 404 {
-		return errors.New("Not Found")
-	}
+	return c.DeleteMembershipContext(context.Background(), id)
+}
 
-	decoder := json.NewDecoder(res.Body)
-	if res.StatusCode != 200 {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	}
-	return err
+func (c *Client) DeleteMembershipContext(ctx context.Context, id int) error {
+	return c.delete(ctx, "/memberships/"+strconv.Itoa(id)+".json")
 }
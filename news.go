@@ -1,48 +1,174 @@
 package redmine
 
 import (
-	"encoding/json"
-	"errors"
+	"context"
 	"strconv"
-	"strings"
 )
 
 type newsResult struct {
-	News []News `json:"news"`
+	News       []News `json:"news"`
+	TotalCount int    `json:"total_count"`
+}
+
+type newsItemResult struct {
+	News News `json:"news"`
+}
+
+type newsRequest struct {
+	News newsUpdate `json:"news"`
+}
+
+type newsUpdate struct {
+	Title       string    `json:"title,omitempty"`
+	Summary     string    `json:"summary,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Uploads     []*Upload `json:"uploads,omitempty"`
 }
 
 type News struct {
-	Id          int    `json:"id"`
-	Project     IdName `json:"project"`
-	Title       string `json:"title"`
-	Summary     string `json:"summary"`
-	Description string `json:"description"`
-	CreatedOn   string `json:"created_on"`
+	Id          int      `json:"id"`
+	Project     IdName   `json:"project"`
+	Author      *IdName  `json:"author"`
+	Title       string   `json:"title"`
+	Summary     string   `json:"summary"`
+	Description string   `json:"description"`
+	CreatedOn   DateTime `json:"created_on"`
+	// Attachments is only filled if requested with NewsIncludeAttachments.
+	Attachments []Attachment `json:"attachments,omitempty"`
+	// Comments is only filled if requested with NewsIncludeComments.
+	Comments []NewsComment `json:"comments,omitempty"`
+	// Uploads attaches uploaded files when creating or updating news.
+	Uploads []*Upload `json:"uploads,omitempty"`
 }
 
+// NewsComment is a comment on news.
+type NewsComment struct {
+	Id      int     `json:"id"`
+	Author  *IdName `json:"author"`
+	Content string  `json:"content"`
+}
+
+const (
+	NewsIncludeAttachments string = "attachments"
+	NewsIncludeComments    string = "comments"
+)
+
 func (c *Client) News(projectId int) ([]News, error) {
-	res, err := c.Get(c.endpoint + "/projects/" + strconv.Itoa(projectId) + "/news.json?key=" + c.apikey + c.getPaginationClause())
+	return c.NewsContext(context.Background(), projectId)
+}
+
+func (c *Client) NewsContext(ctx context.Context, projectId int) ([]News, error) {
+	var r newsResult
+	err := c.get(ctx, c.pathWithParameters("/projects/"+strconv.Itoa(projectId)+"/news.json", c.getPaginationClause()), &r)
 	if err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
+	return r.News, nil
+}
 
-	decoder := json.NewDecoder(res.Body)
-	var r newsResult
-	if res.StatusCode == 404 {
-		return nil, errors.New("Not Found")
+// NewsIterator iterates over the news of a list request, see Paginator.
+type NewsIterator struct {
+	*Paginator
+	page []News
+}
+
+// Item returns the current news.
+func (it *NewsIterator) Item() News {
+	return it.page[it.index]
+}
+
+// IterateNews returns an iterator over all news of a project or, if projectId is 0, of all projects. Every request
+// fetches up to pageSize news; values below 1 use Redmine's default page size.
+func (c *Client) IterateNews(ctx context.Context, projectId int, pageSize int) *NewsIterator {
+	path := "/news.json"
+	if projectId > 0 {
+		path = "/projects/" + strconv.Itoa(projectId) + "/news.json"
 	}
-	if res.StatusCode != 200 {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
+
+	it := &NewsIterator{}
+	it.Paginator = newPaginator(ctx, pageSize, func(ctx context.Context, pagination ...string) (int, int, error) {
+		var r newsResult
+		err := c.get(ctx, c.pathWithParameters(path, pagination...), &r)
+		if err != nil {
+			return 0, 0, err
 		}
-	} else {
-		err = decoder.Decode(&r)
+		it.page = r.News
+		return len(r.News), r.TotalCount, nil
+	})
+	return it
+}
+
+// AllNews returns the news of all projects visible to the user.
+func (c *Client) AllNews() ([]News, error) {
+	return c.AllNewsContext(context.Background())
+}
+
+// AllNewsContext is like AllNews but binds the requests to ctx.
+func (c *Client) AllNewsContext(ctx context.Context) ([]News, error) {
+	var news []News
+	it := c.IterateNews(ctx, 0, c.Limit)
+	for it.Next() {
+		news = append(news, it.Item())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
 	}
+	return news, nil
+}
+
+// NewsItem returns the news with the given id. includes may contain NewsIncludeAttachments and NewsIncludeComments.
+func (c *Client) NewsItem(id int, includes ...string) (*News, error) {
+	return c.NewsItemContext(context.Background(), id, includes...)
+}
+
+// NewsItemContext is like NewsItem but binds the request to ctx.
+func (c *Client) NewsItemContext(ctx context.Context, id int, includes ...string) (*News, error) {
+	var r newsItemResult
+	err := c.get(ctx, c.pathWithParameters("/news/"+strconv.Itoa(id)+".json", includeParameter(includes)), &r)
 	if err != nil {
 		return nil, err
 	}
-	return r.News, nil
+	return &r.News, nil
+}
+
+// CreateNews publishes news with title, summary, description and uploads of news in the project with the id
+// projectId. This requires Redmine 5.1 or later.
+func (c *Client) CreateNews(projectId int, news News) error {
+	return c.CreateNewsContext(context.Background(), projectId, news)
+}
+
+// CreateNewsContext is like CreateNews but binds the request to ctx.
+func (c *Client) CreateNewsContext(ctx context.Context, projectId int, news News) error {
+	return c.post(ctx, "/projects/"+strconv.Itoa(projectId)+"/news.json", newNewsRequest(news), nil)
+}
+
+// UpdateNews changes title, summary and description of the news with the id news.Id and attaches its uploads. Empty
+// fields keep their current values, f. e. UpdateNews(News{Id: 1, Title: "x"}) changes the title only. This requires
+// Redmine 5.1 or later.
+func (c *Client) UpdateNews(news News) error {
+	return c.UpdateNewsContext(context.Background(), news)
+}
+
+// UpdateNewsContext is like UpdateNews but binds the request to ctx.
+func (c *Client) UpdateNewsContext(ctx context.Context, news News) error {
+	return c.put(ctx, "/news/"+strconv.Itoa(news.Id)+".json", newNewsRequest(news), nil)
+}
+
+// DeleteNews deletes the news with the given id. This requires Redmine 5.1 or later.
+func (c *Client) DeleteNews(id int) error {
+	return c.DeleteNewsContext(context.Background(), id)
+}
+
+// DeleteNewsContext is like DeleteNews but binds the request to ctx.
+func (c *Client) DeleteNewsContext(ctx context.Context, id int) error {
+	return c.delete(ctx, "/news/"+strconv.Itoa(id)+".json")
+}
+
+func newNewsRequest(news News) newsRequest {
+	return newsRequest{News: newsUpdate{
+		Title:       news.Title,
+		Summary:     news.Summary,
+		Description: news.Description,
+		Uploads:     news.Uploads,
+	}}
 }
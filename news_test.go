@@ -0,0 +1,131 @@
+package redmine
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_NewsItem(t *testing.T) {
+	t.Run("should parse author, attachments and comments", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/news/3.json", r.URL.Path)
+			assert.Equal(t, "attachments,comments", r.URL.Query().Get("include"))
+			_, _ = fmt.Fprintln(w, `{
+  "news": {
+    "id": 3,
+    "project": {"id": 1, "name": "example project"},
+    "author": {"id": 1, "name": "Redmine Admin"},
+    "title": "Release 1.0",
+    "summary": "Version 1.0 is available",
+    "description": "Get it while it's hot.",
+    "created_on": "2021-03-05T12:00:00Z",
+    "attachments": [{"id": 12, "filename": "release-notes.pdf"}],
+    "comments": [{"id": 1, "author": {"id": 2, "name": "John Smith"}, "content": "Great!"}]
+  }
+}`)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		actual, err := sut.NewsItem(3, NewsIncludeAttachments, NewsIncludeComments)
+
+		require.NoError(t, err)
+		assert.Equal(t, "Release 1.0", actual.Title)
+		assert.Equal(t, &IdName{Id: 1, Name: "Redmine Admin"}, actual.Author)
+		assert.Equal(t, []Attachment{{Id: 12, Filename: "release-notes.pdf"}}, actual.Attachments)
+		assert.Equal(t, []NewsComment{{Id: 1, Author: &IdName{Id: 2, Name: "John Smith"}, Content: "Great!"}}, actual.Comments)
+	})
+}
+
+func TestClient_AllNews(t *testing.T) {
+	t.Run("should list the news of all projects", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/news.json", r.URL.Path)
+			_, _ = fmt.Fprintln(w, `{"news": [{"id": 3, "title": "Release 1.0"}, {"id": 4, "title": "Maintenance"}], "total_count": 2}`)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		actual, err := sut.AllNews()
+
+		require.NoError(t, err)
+		require.Len(t, actual, 2)
+		assert.Equal(t, "Maintenance", actual[1].Title)
+	})
+}
+
+func TestClient_CreateNews(t *testing.T) {
+	t.Run("should post the news with uploads", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "/projects/1/news.json", r.URL.Path)
+			body, _ := ioutil.ReadAll(r.Body)
+			assert.JSONEq(t, `{"news": {"title": "Release 1.0", "description": "Get it", "uploads": [{"token": "12.abc", "filename": "notes.pdf", "content_type": ""}]}}`, string(body))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		err := sut.CreateNews(1, News{
+			Title:       "Release 1.0",
+			Description: "Get it",
+			Author:      &IdName{Id: 1},
+			Uploads:     []*Upload{{Token: "12.abc", Filename: "notes.pdf"}},
+		})
+
+		require.NoError(t, err)
+	})
+}
+
+func TestClient_UpdateNews(t *testing.T) {
+	t.Run("should put the news", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPut, r.Method)
+			assert.Equal(t, "/news/3.json", r.URL.Path)
+			body, _ := ioutil.ReadAll(r.Body)
+			assert.JSONEq(t, `{"news": {"title": "Release 1.0.1", "summary": "Bugfix release"}}`, string(body))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		err := sut.UpdateNews(News{Id: 3, Title: "Release 1.0.1", Summary: "Bugfix release"})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should only send the fields which are set", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			assert.JSONEq(t, `{"news": {"title": "x"}}`, string(body))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		err := sut.UpdateNews(News{Id: 1, Title: "x"})
+
+		require.NoError(t, err)
+	})
+}
+
+func TestClient_DeleteNews(t *testing.T) {
+	t.Run("should delete the news", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodDelete, r.Method)
+			assert.Equal(t, "/news/3.json", r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		err := sut.DeleteNews(3)
+
+		require.NoError(t, err)
+	})
+}
@@ -0,0 +1,139 @@
+package redmine
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// Option configures a Client created by NewClient.
+type Option func(o *options)
+
+// Logger receives a line for every request sent by the client. *log.Logger satisfies this interface.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+type options struct {
+	auth          Authenticator
+	httpClient    *http.Client
+	transport     http.RoundTripper
+	tlsConfig     *tls.Config
+	proxy         func(*http.Request) (*url.URL, error)
+	timeout       time.Duration
+	userAgent     string
+	pageSize      int
+	logger        Logger
+	retryPolicy   RetryPolicy
+	rateLimiter   *tokenBucket
+	maxConcurrent int
+}
+
+// WithAPIKey authenticates all requests with the API key of a Redmine user. It is a shortcut for
+// WithAuthenticator(APIKeyAuth(apikey)).
+func WithAPIKey(apikey string) Option {
+	return WithAuthenticator(APIKeyAuth(apikey))
+}
+
+// WithAuthenticator authenticates all requests with auth, f. e. BasicAuth or AnonymousAuth. Clients without this
+// option send anonymous requests.
+func WithAuthenticator(auth Authenticator) Option {
+	return func(o *options) {
+		o.auth = auth
+	}
+}
+
+// WithHTTPClient uses a copy of httpClient to send requests instead of a new http.Client. The transport related
+// options are applied on top of it.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(o *options) {
+		o.httpClient = httpClient
+	}
+}
+
+// WithTransport uses transport to send requests.
+func WithTransport(transport http.RoundTripper) Option {
+	return func(o *options) {
+		o.transport = transport
+	}
+}
+
+// WithTLSConfig uses tlsConfig for HTTPS connections, f. e. to trust a private CA or to skip certificate verification.
+// It has no effect if a transport other than *http.Transport is used.
+func WithTLSConfig(tlsConfig *tls.Config) Option {
+	return func(o *options) {
+		o.tlsConfig = tlsConfig
+	}
+}
+
+// WithProxy determines the proxy for every request with proxy, f. e. http.ProxyURL or http.ProxyFromEnvironment.
+// It has no effect if a transport other than *http.Transport is used.
+func WithProxy(proxy func(*http.Request) (*url.URL, error)) Option {
+	return func(o *options) {
+		o.proxy = proxy
+	}
+}
+
+// WithTimeout limits the time of every single HTTP request including reading the response body.
+func WithTimeout(timeout time.Duration) Option {
+	return func(o *options) {
+		o.timeout = timeout
+	}
+}
+
+// WithUserAgent sends userAgent in the User-Agent header of every request.
+func WithUserAgent(userAgent string) Option {
+	return func(o *options) {
+		o.userAgent = userAgent
+	}
+}
+
+// WithPageSize sets the number of items which list endpoints request per page. Without this option Redmine's
+// default page size is used.
+func WithPageSize(pageSize int) Option {
+	return func(o *options) {
+		o.pageSize = pageSize
+	}
+}
+
+// WithLogger logs method, URL and outcome of every request to logger. API keys in URLs are masked.
+func WithLogger(logger Logger) Option {
+	return func(o *options) {
+		o.logger = logger
+	}
+}
+
+func (o *options) buildHTTPClient() *http.Client {
+	httpClient := &http.Client{}
+	if o.httpClient != nil {
+		clientCopy := *o.httpClient
+		httpClient = &clientCopy
+	}
+	if o.timeout > 0 {
+		httpClient.Timeout = o.timeout
+	}
+
+	transport := o.transport
+	if transport == nil {
+		transport = httpClient.Transport
+	}
+	if o.tlsConfig != nil || o.proxy != nil {
+		if transport == nil {
+			transport = http.DefaultTransport
+		}
+		if t, ok := transport.(*http.Transport); ok {
+			t = t.Clone()
+			if o.tlsConfig != nil {
+				t.TLSClientConfig = o.tlsConfig
+			}
+			if o.proxy != nil {
+				t.Proxy = o.proxy
+			}
+			transport = t
+		}
+	}
+	httpClient.Transport = transport
+
+	return httpClient
+}
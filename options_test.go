@@ -0,0 +1,73 @@
+package redmine
+
+import (
+	"crypto/tls"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+type recordingLogger struct {
+	lines []string
+}
+
+func (l *recordingLogger) Printf(format string, v ...interface{}) {
+	l.lines = append(l.lines, fmt.Sprintf(format, v...))
+}
+
+func TestNewClient(t *testing.T) {
+	t.Run("should not use http.DefaultClient", func(t *testing.T) {
+		sut := NewClient("http://redmine.example.com")
+
+		assert.NotSame(t, http.DefaultClient, sut.Client)
+		assert.Equal(t, NoSetting, sut.Limit)
+		assert.Equal(t, NoSetting, sut.Offset)
+	})
+
+	t.Run("should copy given http client and apply timeout", func(t *testing.T) {
+		httpClient := &http.Client{Timeout: time.Minute}
+
+		sut := NewClient("http://redmine.example.com", WithHTTPClient(httpClient), WithTimeout(time.Second))
+
+		assert.Equal(t, time.Second, sut.Client.Timeout)
+		assert.Equal(t, time.Minute, httpClient.Timeout)
+	})
+
+	t.Run("should apply TLS config and proxy to a copy of the default transport", func(t *testing.T) {
+		tlsConfig := &tls.Config{InsecureSkipVerify: true}
+		proxyURL, _ := url.Parse("http://proxy.example.com:3128")
+
+		sut := NewClient("https://redmine.example.com", WithTLSConfig(tlsConfig), WithProxy(http.ProxyURL(proxyURL)))
+
+		transport, ok := sut.Client.Transport.(*http.Transport)
+		require.True(t, ok)
+		assert.Same(t, tlsConfig, transport.TLSClientConfig)
+		actualProxy, err := transport.Proxy(&http.Request{})
+		require.NoError(t, err)
+		assert.Equal(t, proxyURL, actualProxy)
+		assert.NotSame(t, http.DefaultTransport, transport)
+	})
+
+	t.Run("should send user agent and page size and log requests", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "godmine/1.0", r.Header.Get("User-Agent"))
+			assert.Equal(t, "25", r.URL.Query().Get("limit"))
+			_, _ = fmt.Fprintln(w, `{"roles": []}`)
+		}))
+		defer ts.Close()
+		logger := &recordingLogger{}
+
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"), WithUserAgent("godmine/1.0"), WithPageSize(25), WithLogger(logger))
+
+		_, err := sut.Roles()
+
+		require.NoError(t, err)
+		require.Len(t, logger.lines, 1)
+		assert.Equal(t, "GET "+ts.URL+"/roles.json?limit=25: 200 OK", logger.lines[0])
+	})
+}
@@ -0,0 +1,167 @@
+package redmine
+
+import (
+	"context"
+	"strconv"
+	"sync"
+)
+
+// pageFetcher fetches a single page of a list endpoint. parameters contain the limit and offset parameters of the
+// page. It returns the number of items on the page and the total number of items reported by Redmine (or 0 if the
+// endpoint does not report it).
+type pageFetcher func(ctx context.Context, parameters ...string) (count int, total int, err error)
+
+// Paginator lazily walks the pages of a list endpoint. It is embedded into the typed iterators like IssueIterator
+// which provide access to the current item:
+//
+//	it := client.IterateIssues(ctx, filter, 100)
+//	for it.Next() {
+//		issue := it.Item()
+//		// ...
+//	}
+//	if err := it.Err(); err != nil {
+//		// ...
+//	}
+//
+// A page is only fetched when all items of the previous page were consumed. Iteration stops after the last page, on
+// the first error and as soon as a page is empty so that a server reporting a wrong total count cannot cause an
+// endless loop. Paginators are not safe for concurrent use.
+type Paginator struct {
+	ctx      context.Context
+	fetch    pageFetcher
+	pageSize int
+	offset   int
+	index    int
+	length   int
+	done     bool
+	err      error
+}
+
+func newPaginator(ctx context.Context, pageSize int, fetch pageFetcher) *Paginator {
+	return &Paginator{
+		ctx:      ctx,
+		fetch:    fetch,
+		pageSize: pageSize,
+		index:    -1,
+	}
+}
+
+// Next advances to the next item and fetches the next page if necessary. It returns false when there are no more
+// items or an error occurred.
+func (p *Paginator) Next() bool {
+	if p.err != nil {
+		return false
+	}
+	p.index++
+	if p.index < p.length {
+		return true
+	}
+	if p.done {
+		return false
+	}
+	if p.err = p.ctx.Err(); p.err != nil {
+		return false
+	}
+
+	count, total, err := p.fetch(p.ctx, paginationParameters(p.offset, p.pageSize)...)
+	if err != nil {
+		p.err = err
+		return false
+	}
+
+	p.index = 0
+	p.length = count
+	p.offset += count
+	if count == 0 || p.offset >= total {
+		p.done = true
+	}
+	return count > 0
+}
+
+// Err returns the error which stopped the iteration, if any.
+func (p *Paginator) Err() error {
+	return p.err
+}
+
+func paginationParameters(offset, limit int) []string {
+	parameters := []string{"offset=" + strconv.Itoa(offset)}
+	if limit > 0 {
+		parameters = append(parameters, "limit="+strconv.Itoa(limit))
+	}
+	return parameters
+}
+
+// itemsFetcher fetches a single page of a list endpoint like pageFetcher but returns the items of the page, too.
+type itemsFetcher func(ctx context.Context, parameters ...string) (items interface{}, count int, total int, err error)
+
+// fetchPagesInParallel fetches the first page to learn the total count and then all remaining pages with up to
+// workers concurrent requests. The returned slice contains the items of every page in page order. The first error
+// cancels all outstanding requests and is returned.
+//
+// The page size of the remaining pages is the number of items on the first page because Redmine caps the requested
+// limit (to 100 by default).
+func fetchPagesInParallel(ctx context.Context, workers, pageSize int, fetch itemsFetcher) ([]interface{}, error) {
+	items, count, total, err := fetch(ctx, paginationParameters(0, pageSize)...)
+	if err != nil {
+		return nil, err
+	}
+	pages := []interface{}{items}
+	if count == 0 || count >= total {
+		return pages, nil
+	}
+
+	pageSize = count
+	remaining := (total - count + pageSize - 1) / pageSize
+	pages = append(pages, make([]interface{}, remaining)...)
+	if workers < 1 {
+		workers = 1
+	}
+	if workers > remaining {
+		workers = remaining
+	}
+
+	workerCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	jobs := make(chan int)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for page := range jobs {
+				items, _, _, err := fetch(workerCtx, paginationParameters(page*pageSize, pageSize)...)
+				if err != nil {
+					once.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					continue
+				}
+				pages[page] = items
+			}
+		}()
+	}
+
+schedule:
+	for page := 1; page <= remaining; page++ {
+		select {
+		case jobs <- page:
+		case <-workerCtx.Done():
+			break schedule
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return pages, nil
+}
@@ -0,0 +1,256 @@
+package redmine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// projectPages serves total projects in pages of the requested limit and records the requested offsets.
+func projectPages(t *testing.T, total int, offsets *[]string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/projects.json", r.URL.Path)
+		*offsets = append(*offsets, r.URL.Query().Get("offset"))
+
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		projects := ""
+		for id := offset + 1; id <= offset+limit && id <= total; id++ {
+			if projects != "" {
+				projects += ","
+			}
+			projects += fmt.Sprintf(`{"id": %d}`, id)
+		}
+		_, _ = fmt.Fprintf(w, `{"projects": [%s], "total_count": %d, "offset": %d, "limit": %d}`, projects, total, offset, limit)
+	}))
+}
+
+func TestPaginator(t *testing.T) {
+	t.Run("should fetch all pages lazily", func(t *testing.T) {
+		var offsets []string
+		ts := projectPages(t, 5, &offsets)
+		defer ts.Close()
+		sut := NewClient(ts.URL)
+
+		it := sut.IterateProjects(context.Background(), 2)
+		assert.Empty(t, offsets)
+
+		var ids []int
+		for it.Next() {
+			ids = append(ids, it.Item().Id)
+		}
+
+		require.NoError(t, it.Err())
+		assert.Equal(t, []int{1, 2, 3, 4, 5}, ids)
+		assert.Equal(t, []string{"0", "2", "4"}, offsets)
+		assert.Equal(t, NoSetting, sut.Limit)
+		assert.Equal(t, NoSetting, sut.Offset)
+	})
+
+	t.Run("should stop on an empty page although the total count is higher", func(t *testing.T) {
+		requests := 0
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if r.URL.Query().Get("offset") == "0" {
+				_, _ = fmt.Fprintln(w, `{"projects": [{"id": 1}], "total_count": 10}`)
+				return
+			}
+			_, _ = fmt.Fprintln(w, `{"projects": [], "total_count": 10}`)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL)
+
+		it := sut.IterateProjects(context.Background(), 0)
+		count := 0
+		for it.Next() {
+			count++
+		}
+
+		require.NoError(t, it.Err())
+		assert.Equal(t, 1, count)
+		assert.Equal(t, 2, requests)
+	})
+
+	t.Run("should fetch a single page if the endpoint does not report a total count", func(t *testing.T) {
+		requests := 0
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			assert.Equal(t, "/projects/1/wiki/index.json", r.URL.Path)
+			_, _ = fmt.Fprintln(w, `{"wiki_pages": [{"title": "Wiki"}, {"title": "Other"}]}`)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL)
+
+		it := sut.IterateWikiPages(context.Background(), 1, 0)
+		var titles []string
+		for it.Next() {
+			titles = append(titles, it.Item().Title)
+		}
+
+		require.NoError(t, it.Err())
+		assert.Equal(t, []string{"Wiki", "Other"}, titles)
+		assert.Equal(t, 1, requests)
+	})
+
+	t.Run("should stop on the first error", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("offset") == "0" {
+				_, _ = fmt.Fprintln(w, `{"projects": [{"id": 1}], "total_count": 2}`)
+				return
+			}
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL)
+
+		it := sut.IterateProjects(context.Background(), 1)
+
+		assert.True(t, it.Next())
+		assert.False(t, it.Next())
+		assert.True(t, errors.Is(it.Err(), ErrServer))
+		assert.False(t, it.Next())
+	})
+
+	t.Run("should not fetch pages after the context was cancelled", func(t *testing.T) {
+		var offsets []string
+		ts := projectPages(t, 5, &offsets)
+		defer ts.Close()
+		sut := NewClient(ts.URL)
+		ctx, cancel := context.WithCancel(context.Background())
+
+		it := sut.IterateProjects(ctx, 2)
+		assert.True(t, it.Next())
+		assert.True(t, it.Next())
+		cancel()
+
+		assert.False(t, it.Next())
+		assert.Equal(t, context.Canceled, it.Err())
+		assert.Equal(t, []string{"0"}, offsets)
+	})
+
+	t.Run("should pass filter parameters with every page", func(t *testing.T) {
+		var queries []string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			queries = append(queries, r.URL.RawQuery)
+			_, _ = fmt.Fprintln(w, `{"time_entries": [{"id": 1}], "total_count": 2}`)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL)
+		filter := Filter{}
+		filter.AddPair("user_id", "me")
+
+		it := sut.IterateTimeEntries(context.Background(), filter, 1)
+		for it.Next() {
+		}
+
+		require.NoError(t, it.Err())
+		assert.Equal(t, []string{"offset=0&limit=1&user_id=me", "offset=1&limit=1&user_id=me"}, queries)
+	})
+}
+
+// issuePages serves total issues in pages of the requested limit. Earlier pages are answered later so that the
+// responses arrive out of order.
+func issuePages(t *testing.T, total int, inFlight, maxInFlight *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		current := atomic.AddInt32(inFlight, 1)
+		defer atomic.AddInt32(inFlight, -1)
+		for {
+			max := atomic.LoadInt32(maxInFlight)
+			if current <= max || atomic.CompareAndSwapInt32(maxInFlight, max, current) {
+				break
+			}
+		}
+
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+		if offset > 0 {
+			time.Sleep(time.Duration(total-offset) * time.Millisecond)
+		}
+		issues := ""
+		for id := offset + 1; id <= offset+limit && id <= total; id++ {
+			if issues != "" {
+				issues += ","
+			}
+			issues += fmt.Sprintf(`{"id": %d}`, id)
+		}
+		_, _ = fmt.Fprintf(w, `{"issues": [%s], "total_count": %d}`, issues, total)
+	}))
+}
+
+func Test_fetchPagesInParallel(t *testing.T) {
+	t.Run("should return the pages in order with bounded concurrency", func(t *testing.T) {
+		var inFlight, maxInFlight int32
+		ts := issuePages(t, 50, &inFlight, &maxInFlight)
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithPageSize(3))
+
+		actual, err := sut.IssuesByFilterParallel(nil, 4)
+
+		require.NoError(t, err)
+		require.Len(t, actual, 50)
+		for i, issue := range actual {
+			assert.Equal(t, i+1, issue.Id)
+		}
+		assert.True(t, maxInFlight <= 4, "at most 4 concurrent requests expected but got %d", maxInFlight)
+	})
+
+	t.Run("should use the size of the first page for all pages", func(t *testing.T) {
+		var queries []string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			queries = append(queries, r.URL.RawQuery)
+			_, _ = fmt.Fprintln(w, `{"time_entries": [{"id": 1}, {"id": 2}], "total_count": 4}`)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithPageSize(500))
+
+		actual, err := sut.TimeEntriesWithFilterParallel(Filter{}, 1)
+
+		require.NoError(t, err)
+		assert.Len(t, actual, 4)
+		assert.Equal(t, []string{"offset=0&limit=500", "offset=2&limit=2"}, queries)
+	})
+
+	t.Run("should abort on the first error", func(t *testing.T) {
+		var requests int32
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&requests, 1)
+			if r.URL.Query().Get("offset") == "1" {
+				w.WriteHeader(http.StatusNotFound)
+				return
+			}
+			if r.URL.Query().Get("offset") != "0" {
+				time.Sleep(10 * time.Millisecond)
+			}
+			_, _ = fmt.Fprintln(w, `{"issues": [{"id": 1}], "total_count": 1000}`)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL)
+
+		actual, err := sut.IssuesByFilterParallel(nil, 2)
+
+		assert.Nil(t, actual)
+		assert.True(t, errors.Is(err, ErrNotFound))
+		assert.True(t, atomic.LoadInt32(&requests) < 10, "expected the remaining pages to be skipped")
+	})
+
+	t.Run("should not start workers if everything fits on the first page", func(t *testing.T) {
+		var inFlight, maxInFlight int32
+		ts := issuePages(t, 2, &inFlight, &maxInFlight)
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithPageSize(25))
+
+		actual, err := sut.IssuesByFilterParallel(nil, 4)
+
+		require.NoError(t, err)
+		assert.Len(t, actual, 2)
+		assert.Equal(t, int32(1), maxInFlight)
+	})
+}
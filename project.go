@@ -1,14 +1,43 @@
 package redmine
 
 import (
-	"encoding/json"
+	"context"
 	"errors"
-	"fmt"
-	"net/http"
+	"net/url"
 	"strconv"
-	"strings"
 )
 
+// ProjectStatus describes the lifecycle state of a project.
+type ProjectStatus int
+
+const (
+	// ProjectStatusActive marks a project which can be read and modified.
+	ProjectStatusActive ProjectStatus = 1
+	// ProjectStatusClosed marks a read-only project, see CloseProject.
+	ProjectStatusClosed ProjectStatus = 5
+	// ProjectStatusArchived marks a project which is hidden from all users except administrators, see ArchiveProject.
+	ProjectStatusArchived ProjectStatus = 9
+	// ProjectStatusScheduledForDeletion marks a project which is about to be deleted by a background job.
+	//
+	// since Redmine 5.1.0
+	ProjectStatusScheduledForDeletion ProjectStatus = 10
+)
+
+// String returns a human readable name of the status.
+func (s ProjectStatus) String() string {
+	switch s {
+	case ProjectStatusActive:
+		return "active"
+	case ProjectStatusClosed:
+		return "closed"
+	case ProjectStatusArchived:
+		return "archived"
+	case ProjectStatusScheduledForDeletion:
+		return "scheduled for deletion"
+	}
+	return "status " + strconv.Itoa(int(s))
+}
+
 type projectRequest struct {
 	Project Project `json:"project"`
 }
@@ -18,7 +47,8 @@ type projectResult struct {
 }
 
 type projectsResult struct {
-	Projects []Project `json:"projects"`
+	Projects   []Project `json:"projects"`
+	TotalCount int       `json:"total_count"`
 }
 
 // Project contains a Redmine API project object according Redmine 4.1 REST API.
@@ -51,174 +81,219 @@ type Project struct {
 	// nested project) all members from the parent project will apply also to this project.
 	InheritMembers bool `json:"inherit_members"`
 	// CreatedOn contains a timestamp of when the project was created.
-	CreatedOn string `json:"created_on"`
+	CreatedOn DateTime `json:"created_on"`
 	// UpdatedOn contains the timestamp of when the project was last updated.
-	UpdatedOn string `json:"updated_on"`
+	UpdatedOn DateTime `json:"updated_on"`
+	// Parent contains id and name of the parent project, if any. It is read-only, use ParentID to set the parent.
+	Parent *IdName `json:"parent,omitempty"`
+	// DefaultVersion contains the version which is assigned to new issues by default. Set DefaultVersionId to change it.
+	DefaultVersion *IdName `json:"default_version,omitempty"`
+	// DefaultAssignee contains the user who is assigned to new issues by default. Set DefaultAssignedToId to change it.
+	DefaultAssignee *IdName `json:"default_assignee,omitempty"`
+
+	CustomFields []*CustomField `json:"custom_fields,omitempty"`
+
+	// Trackers contains the trackers enabled in the project. It is filled with the include ProjectIncludeTrackers.
+	Trackers []IdName `json:"trackers,omitempty"`
+	// IssueCategories is filled with the include ProjectIncludeIssueCategories.
+	IssueCategories []IdName `json:"issue_categories,omitempty"`
+	// EnabledModules contains the modules enabled in the project. It is filled with the include
+	// ProjectIncludeEnabledModules.
+	EnabledModules []IdName `json:"enabled_modules,omitempty"`
+	// TimeEntryActivities is filled with the include ProjectIncludeTimeEntryActivities.
+	TimeEntryActivities []IdName `json:"time_entry_activities,omitempty"`
+	// IssueCustomFields contains the issue custom fields enabled in the project. It is filled with the include
+	// ProjectIncludeIssueCustomFields.
+	IssueCustomFields []IdName `json:"issue_custom_fields,omitempty"`
 
-	Status int `json:"status,omitempty"`
+	// TrackerIds sets the trackers enabled in the project on CreateProject and UpdateProject. It is write-only and
+	// left unchanged if empty.
+	TrackerIds []int `json:"tracker_ids,omitempty"`
+	// EnabledModuleNames sets the modules enabled in the project, f. e. "issue_tracking" or "wiki". It is write-only
+	// and left unchanged if empty.
+	EnabledModuleNames []string `json:"enabled_module_names,omitempty"`
+	// IssueCustomFieldIds sets the issue custom fields enabled in the project. It is write-only and left unchanged if
+	// empty.
+	IssueCustomFieldIds []int `json:"issue_custom_field_ids,omitempty"`
+	// DefaultVersionId sets the default version of new issues. It is write-only and left unchanged if 0.
+	DefaultVersionId int `json:"default_version_id,omitempty"`
+	// DefaultAssignedToId sets the default assignee of new issues. It is write-only and left unchanged if 0.
+	DefaultAssignedToId int `json:"default_assigned_to_id,omitempty"`
+
+	// Status contains the lifecycle state of the project. It is read-only, use ArchiveProject, UnarchiveProject,
+	// CloseProject and ReopenProject to change it.
+	Status ProjectStatus `json:"status,omitempty"`
 }
 
-// Project returns a single project without additional fields.
-func (c *Client) Project(id int) (*Project, error) {
-	res, err := c.Get(c.endpoint + "/projects/" + strconv.Itoa(id) + ".json?" + c.apiKeyParameter())
-	if err != nil {
-		return nil, err
+// Includes for Project, Projects, IterateProjects and CreateProject.
+const (
+	ProjectIncludeTrackers            string = "trackers"
+	ProjectIncludeIssueCategories     string = "issue_categories"
+	ProjectIncludeEnabledModules      string = "enabled_modules"
+	ProjectIncludeTimeEntryActivities string = "time_entry_activities"
+	// ProjectIncludeIssueCustomFields (since Redmine 4.2).
+	ProjectIncludeIssueCustomFields string = "issue_custom_fields"
+)
+
+// Project returns a single project. Without includes like ProjectIncludeTrackers the project contains the general
+// fields only.
+func (c *Client) Project(id int, includes ...string) (*Project, error) {
+	return c.ProjectContext(context.Background(), id, includes...)
+}
+
+// ProjectContext is like Project but binds the request to ctx.
+func (c *Client) ProjectContext(ctx context.Context, id int, includes ...string) (*Project, error) {
+	return c.getProject(ctx, strconv.Itoa(id), includes)
+}
+
+// ProjectByIdentifier returns the project with the given identifier like "exampleproject". includes may contain the
+// same values as for Project.
+func (c *Client) ProjectByIdentifier(identifier string, includes ...string) (*Project, error) {
+	return c.ProjectByIdentifierContext(context.Background(), identifier, includes...)
+}
+
+// ProjectByIdentifierContext is like ProjectByIdentifier but binds the request to ctx.
+func (c *Client) ProjectByIdentifierContext(ctx context.Context, identifier string, includes ...string) (*Project, error) {
+	if identifier == "" {
+		return nil, errors.New("project identifier must not be empty")
 	}
-	defer res.Body.Close()
+	return c.getProject(ctx, url.PathEscape(identifier), includes)
+}
 
-	decoder := json.NewDecoder(res.Body)
+// getProject fetches a project by its id or identifier, which Redmine both accepts in the URL path.
+func (c *Client) getProject(ctx context.Context, idOrIdentifier string, includes []string) (*Project, error) {
 	var r projectResult
-	if res.StatusCode == http.StatusNotFound {
-		return nil, fmt.Errorf("project (id: %d) was not found", id)
-	}
-	if !isHTTPStatusSuccessful(res.StatusCode, []int{http.StatusOK}) {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	} else {
-		err = decoder.Decode(&r)
-	}
+	err := c.get(ctx, c.pathWithParameters("/projects/"+idOrIdentifier+".json", includeParameter(includes)), &r)
 	if err != nil {
 		return nil, err
 	}
 	return &r.Project, nil
 }
 
-func isHTTPStatusSuccessful(httpStatus int, acceptedStatuses []int) bool {
-	for _, acceptedStatus := range acceptedStatuses {
-		if httpStatus == acceptedStatus {
-			return true
-		}
-	}
-
-	return false
+// Projects returns a page of the projects visible to the user. includes may contain the same values as for Project.
+func (c *Client) Projects(includes ...string) ([]Project, error) {
+	return c.ProjectsContext(context.Background(), includes...)
 }
 
-func (c *Client) Projects() ([]Project, error) {
-	parameters := c.concatParameters(c.apiKeyParameter(), c.getPaginationClause())
-	res, err := c.Get(c.endpoint + "/projects.json?" + parameters)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	decoder := json.NewDecoder(res.Body)
+// ProjectsContext is like Projects but binds the request to ctx.
+func (c *Client) ProjectsContext(ctx context.Context, includes ...string) ([]Project, error) {
 	var r projectsResult
-	if !isHTTPStatusSuccessful(res.StatusCode, []int{http.StatusOK}) {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	} else {
-		err = decoder.Decode(&r)
-	}
+	err := c.get(ctx, c.pathWithParameters("/projects.json", c.getPaginationClause(), includeParameter(includes)), &r)
 	if err != nil {
 		return nil, err
 	}
 	return r.Projects, nil
 }
 
-func (c *Client) CreateProject(project Project) (*Project, error) {
-	var ir projectRequest
-	ir.Project = project
-	s, err := json.Marshal(ir)
-	if err != nil {
-		return nil, err
-	}
+// ProjectIterator iterates over the projects of a list request, see Paginator.
+type ProjectIterator struct {
+	*Paginator
+	page []Project
+}
 
-	parameters := c.concatParameters(c.apiKeyParameter())
-	req, err := http.NewRequest("POST", c.endpoint+"/projects.json?"+parameters, strings.NewReader(string(s)))
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	res, err := c.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	decoder := json.NewDecoder(res.Body)
-	var r projectRequest
-	if !isHTTPStatusSuccessful(res.StatusCode, []int{http.StatusCreated}) {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
+// Item returns the current project.
+func (it *ProjectIterator) Item() Project {
+	return it.page[it.index]
+}
+
+// IterateProjects returns an iterator over all projects visible to the user. Every request fetches up to pageSize
+// projects; values below 1 use Redmine's default page size. includes may contain the same values as for Project.
+func (c *Client) IterateProjects(ctx context.Context, pageSize int, includes ...string) *ProjectIterator {
+	include := includeParameter(includes)
+
+	it := &ProjectIterator{}
+	it.Paginator = newPaginator(ctx, pageSize, func(ctx context.Context, pagination ...string) (int, int, error) {
+		var r projectsResult
+		err := c.get(ctx, c.pathWithParameters("/projects.json", append(pagination, include)...), &r)
+		if err != nil {
+			return 0, 0, err
 		}
-	} else {
-		err = decoder.Decode(&r)
-	}
+		it.page = r.Projects
+		return len(r.Projects), r.TotalCount, nil
+	})
+	return it
+}
+
+// CreateProject creates a new project. includes may contain the same values as for Project to receive f. e. the
+// enabled trackers of the created project.
+func (c *Client) CreateProject(project Project, includes ...string) (*Project, error) {
+	return c.CreateProjectContext(context.Background(), project, includes...)
+}
+
+// CreateProjectContext is like CreateProject but binds the request to ctx.
+func (c *Client) CreateProjectContext(ctx context.Context, project Project, includes ...string) (*Project, error) {
+	var r projectResult
+	path := c.pathWithParameters("/projects.json", includeParameter(includes))
+	err := c.post(ctx, path, projectRequest{Project: project}, &r)
 	if err != nil {
 		return nil, err
 	}
 	return &r.Project, nil
 }
 
+// UpdateProject changes the project with the id project.Id. Besides the general fields it sets the write-only fields
+// like TrackerIds and EnabledModuleNames, if they are not empty.
 func (c *Client) UpdateProject(project Project) error {
-	var ir projectRequest
-	ir.Project = project
-	s, err := json.Marshal(ir)
-	if err != nil {
-		return err
-	}
-
-	parameters := c.concatParameters(c.apiKeyParameter())
-	req, err := http.NewRequest("PUT", c.endpoint+"/projects/"+strconv.Itoa(project.Id)+".json?"+parameters, strings.NewReader(string(s)))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	res, err := c.Do(req)
-	if err != nil {
-		return err
-	}
-	defer res.Body.Close()
+	return c.UpdateProjectContext(context.Background(), project)
+}
 
-	if res.StatusCode == http.StatusNotFound {
-		return fmt.Errorf("could not update project (id: %d) because it was not found", project.Id)
-	}
-	if !isHTTPStatusSuccessful(res.StatusCode, []int{http.StatusOK, http.StatusNoContent}) {
-		decoder := json.NewDecoder(res.Body)
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	}
-	if err != nil {
-		return err
-	}
-	return err
+func (c *Client) UpdateProjectContext(ctx context.Context, project Project) error {
+	return c.put(ctx, "/projects/"+strconv.Itoa(project.Id)+".json", projectRequest{Project: project}, nil)
 }
 
 func (c *Client) DeleteProject(id int) error {
-	parameters := c.concatParameters(c.apiKeyParameter())
-	req, err := http.NewRequest("DELETE", c.endpoint+"/projects/"+strconv.Itoa(id)+".json?"+parameters, strings.NewReader(""))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	res, err := c.Do(req)
-	if err != nil {
-		return err
-	}
-	defer res.Body.Close()
+	return c.DeleteProjectContext(context.Background(), id)
+}
 
-	if res.StatusCode == http.StatusNotFound {
-		return fmt.Errorf("could not delete project (id %d) because it was not found", id)
-	}
+func (c *Client) DeleteProjectContext(ctx context.Context, id int) error {
+	return c.delete(ctx, "/projects/"+strconv.Itoa(id)+".json")
+}
 
-	decoder := json.NewDecoder(res.Body)
-	if !isHTTPStatusSuccessful(res.StatusCode, []int{http.StatusOK, http.StatusNoContent}) {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	}
-	return err
+// ArchiveProject archives the project with the given id. Archived projects are hidden from all users except
+// administrators until they are unarchived.
+//
+// since Redmine 5.1.0
+func (c *Client) ArchiveProject(id int) error {
+	return c.ArchiveProjectContext(context.Background(), id)
+}
+
+// ArchiveProjectContext is like ArchiveProject but binds the request to ctx.
+func (c *Client) ArchiveProjectContext(ctx context.Context, id int) error {
+	return c.put(ctx, "/projects/"+strconv.Itoa(id)+"/archive.json", nil, nil)
+}
+
+// UnarchiveProject makes the archived project with the given id active again.
+//
+// since Redmine 5.1.0
+func (c *Client) UnarchiveProject(id int) error {
+	return c.UnarchiveProjectContext(context.Background(), id)
+}
+
+// UnarchiveProjectContext is like UnarchiveProject but binds the request to ctx.
+func (c *Client) UnarchiveProjectContext(ctx context.Context, id int) error {
+	return c.put(ctx, "/projects/"+strconv.Itoa(id)+"/unarchive.json", nil, nil)
+}
+
+// CloseProject closes the project with the given id. Closed projects stay visible but become read-only.
+//
+// since Redmine 5.1.0
+func (c *Client) CloseProject(id int) error {
+	return c.CloseProjectContext(context.Background(), id)
+}
+
+// CloseProjectContext is like CloseProject but binds the request to ctx.
+func (c *Client) CloseProjectContext(ctx context.Context, id int) error {
+	return c.put(ctx, "/projects/"+strconv.Itoa(id)+"/close.json", nil, nil)
+}
+
+// ReopenProject makes the closed project with the given id active again.
+//
+// since Redmine 5.1.0
+func (c *Client) ReopenProject(id int) error {
+	return c.ReopenProjectContext(context.Background(), id)
+}
+
+// ReopenProjectContext is like ReopenProject but binds the request to ctx.
+func (c *Client) ReopenProjectContext(ctx context.Context, id int) error {
+	return c.put(ctx, "/projects/"+strconv.Itoa(id)+"/reopen.json", nil, nil)
 }
@@ -1,16 +1,19 @@
 package redmine
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 )
 
 func TestClient_Project(t *testing.T) {
-	t.Run("should parse general project fields, and ignore module names and trackers from http response", func(t *testing.T) {
+	t.Run("should parse general project fields, trackers and enabled modules from http response", func(t *testing.T) {
 		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			_, _ = fmt.Fprintln(w, `{
   "project": {
@@ -49,7 +52,7 @@ func TestClient_Project(t *testing.T) {
 		}))
 		defer ts.Close()
 
-		sut := NewClient(ts.URL, "apiKey")
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
 
 		actualProject, err := sut.Project(1)
 
@@ -64,9 +67,275 @@ func TestClient_Project(t *testing.T) {
 			Homepage:       "http://github.com/cloudogu/go-redmine",
 			IsPublic:       true,
 			InheritMembers: true,
-			CreatedOn:      "2021-02-19T16:51:03Z",
-			UpdatedOn:      "2021-02-19T16:51:25Z",
+			Status:         ProjectStatusActive,
+			Trackers:       []IdName{{Id: 1, Name: "Bug"}, {Id: 2, Name: "Feature"}},
+			EnabledModules: []IdName{{Id: 71, Name: "issue_tracking"}, {Id: 73, Name: "wiki"}},
+			CreatedOn:      DateTime{time.Date(2021, 2, 19, 16, 51, 3, 0, time.UTC)},
+			UpdatedOn:      DateTime{time.Date(2021, 2, 19, 16, 51, 25, 0, time.UTC)},
 		}
 		assert.Equal(t, expectedProject, actualProject)
 	})
+
+	t.Run("should request includes and parse parent, defaults and issue custom fields", func(t *testing.T) {
+		var query string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query = r.URL.RawQuery
+			_, _ = fmt.Fprintln(w, `{
+  "project": {
+    "id": 2,
+    "name": "child",
+    "parent": {"id": 1, "name": "example project"},
+    "default_version": {"id": 4, "name": "1.0"},
+    "default_assignee": {"id": 5, "name": "Jane Doe"},
+    "issue_categories": [{"id": 6, "name": "Backend"}],
+    "time_entry_activities": [{"id": 8, "name": "Development"}],
+    "issue_custom_fields": [{"id": 9, "name": "Severity"}]
+  }
+}`)
+		}))
+		defer ts.Close()
+
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		actual, err := sut.Project(2, ProjectIncludeIssueCategories, ProjectIncludeTimeEntryActivities,
+			ProjectIncludeIssueCustomFields)
+
+		require.NoError(t, err)
+		assert.Equal(t, "include=issue_categories,time_entry_activities,issue_custom_fields", query)
+		assert.Equal(t, &IdName{Id: 1, Name: "example project"}, actual.Parent)
+		assert.Equal(t, &IdName{Id: 4, Name: "1.0"}, actual.DefaultVersion)
+		assert.Equal(t, &IdName{Id: 5, Name: "Jane Doe"}, actual.DefaultAssignee)
+		assert.Equal(t, []IdName{{Id: 6, Name: "Backend"}}, actual.IssueCategories)
+		assert.Equal(t, []IdName{{Id: 8, Name: "Development"}}, actual.TimeEntryActivities)
+		assert.Equal(t, []IdName{{Id: 9, Name: "Severity"}}, actual.IssueCustomFields)
+	})
+}
+
+func TestClient_ProjectByIdentifier(t *testing.T) {
+	t.Run("should request the project by its identifier", func(t *testing.T) {
+		var path, query string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			path, query = r.URL.Path, r.URL.RawQuery
+			_, _ = fmt.Fprintln(w, `{"project": {"id": 1, "identifier": "exampleproject"}}`)
+		}))
+		defer ts.Close()
+
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		actual, err := sut.ProjectByIdentifier("exampleproject", ProjectIncludeTrackers)
+
+		require.NoError(t, err)
+		assert.Equal(t, "/projects/exampleproject.json", path)
+		assert.Equal(t, "include=trackers", query)
+		assert.Equal(t, 1, actual.Id)
+	})
+
+	t.Run("should return NotFoundError for unknown identifiers", func(t *testing.T) {
+		ts := httptest.NewServer(http.NotFoundHandler())
+		defer ts.Close()
+
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		actual, err := sut.ProjectByIdentifier("missing")
+
+		assert.Nil(t, actual)
+		assert.True(t, errors.Is(err, ErrNotFound))
+	})
+
+	t.Run("should reject an empty identifier without sending a request", func(t *testing.T) {
+		sut := NewClient("http://127.0.0.1:0", WithAPIKey("apiKey"))
+
+		actual, err := sut.ProjectByIdentifier("")
+
+		assert.Nil(t, actual)
+		assert.Error(t, err)
+	})
+}
+
+func TestClient_Projects(t *testing.T) {
+	t.Run("should request includes", func(t *testing.T) {
+		var include string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			include = r.URL.Query().Get("include")
+			_, _ = fmt.Fprintln(w, `{"projects": [{"id": 1, "trackers": [{"id": 1, "name": "Bug"}]}], "total_count": 1}`)
+		}))
+		defer ts.Close()
+
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		actual, err := sut.Projects(ProjectIncludeTrackers)
+
+		require.NoError(t, err)
+		assert.Equal(t, "trackers", include)
+		require.Len(t, actual, 1)
+		assert.Equal(t, []IdName{{Id: 1, Name: "Bug"}}, actual[0].Trackers)
+	})
+}
+
+func TestClient_CreateProject(t *testing.T) {
+	t.Run("should send write-only fields and request includes", func(t *testing.T) {
+		var query string
+		var body map[string]map[string]interface{}
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			query = r.URL.RawQuery
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = fmt.Fprintln(w, `{"project": {"id": 3, "name": "new", "enabled_modules": [{"id": 1, "name": "wiki"}]}}`)
+		}))
+		defer ts.Close()
+
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		actual, err := sut.CreateProject(Project{
+			Name:                "new",
+			Identifier:          "new",
+			TrackerIds:          []int{1, 2},
+			EnabledModuleNames:  []string{"wiki"},
+			IssueCustomFieldIds: []int{9},
+			DefaultVersionId:    4,
+			DefaultAssignedToId: 5,
+		}, ProjectIncludeEnabledModules)
+
+		require.NoError(t, err)
+		assert.Equal(t, "include=enabled_modules", query)
+		assert.Equal(t, []IdName{{Id: 1, Name: "wiki"}}, actual.EnabledModules)
+		project := body["project"]
+		assert.Equal(t, []interface{}{1.0, 2.0}, project["tracker_ids"])
+		assert.Equal(t, []interface{}{"wiki"}, project["enabled_module_names"])
+		assert.Equal(t, []interface{}{9.0}, project["issue_custom_field_ids"])
+		assert.Equal(t, 4.0, project["default_version_id"])
+		assert.Equal(t, 5.0, project["default_assigned_to_id"])
+	})
+}
+
+func TestClient_UpdateProject(t *testing.T) {
+	t.Run("should omit empty write-only and read-only fields", func(t *testing.T) {
+		var body map[string]map[string]interface{}
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_ = json.NewDecoder(r.Body).Decode(&body)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer ts.Close()
+
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		err := sut.UpdateProject(Project{Id: 3, Name: "renamed"})
+
+		require.NoError(t, err)
+		project := body["project"]
+		assert.Equal(t, "renamed", project["name"])
+		for _, key := range []string{"tracker_ids", "enabled_module_names", "issue_custom_field_ids",
+			"default_version_id", "default_assigned_to_id", "trackers", "parent", "default_version"} {
+			assert.NotContains(t, project, key)
+		}
+	})
+}
+
+func TestClient_ProjectLifecycle(t *testing.T) {
+	tests := []struct {
+		name   string
+		call   func(c *Client) error
+		action string
+	}{
+		{"archive", func(c *Client) error { return c.ArchiveProject(3) }, "archive"},
+		{"unarchive", func(c *Client) error { return c.UnarchiveProject(3) }, "unarchive"},
+		{"close", func(c *Client) error { return c.CloseProject(3) }, "close"},
+		{"reopen", func(c *Client) error { return c.ReopenProject(3) }, "reopen"},
+	}
+	for _, tt := range tests {
+		t.Run("should "+tt.name+" the project", func(t *testing.T) {
+			var method, path string
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				method, path = r.Method, r.URL.Path
+				w.WriteHeader(http.StatusNoContent)
+			}))
+			defer ts.Close()
+
+			err := tt.call(NewClient(ts.URL, WithAPIKey("apiKey")))
+
+			require.NoError(t, err)
+			assert.Equal(t, http.MethodPut, method)
+			assert.Equal(t, "/projects/3/"+tt.action+".json", path)
+		})
+	}
+
+	t.Run("should return a typed error if Redmine rejects the transition", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		defer ts.Close()
+
+		err := NewClient(ts.URL, WithAPIKey("apiKey")).ArchiveProject(3)
+
+		assert.True(t, errors.Is(err, ErrAuth))
+	})
+}
+
+func TestProjectStatus_String(t *testing.T) {
+	assert.Equal(t, "active", ProjectStatusActive.String())
+	assert.Equal(t, "closed", ProjectStatusClosed.String())
+	assert.Equal(t, "archived", ProjectStatusArchived.String())
+	assert.Equal(t, "scheduled for deletion", ProjectStatusScheduledForDeletion.String())
+	assert.Equal(t, "status 42", ProjectStatus(42).String())
+}
+
+func TestClient_CreateMembershipByProjectID(t *testing.T) {
+	newServer := func(status ProjectStatus, posted *bool) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPost {
+				*posted = true
+				w.WriteHeader(http.StatusCreated)
+				_, _ = fmt.Fprintln(w, `{"membership": {"id": 7, "project": {"id": 3, "name": "p"}}}`)
+				return
+			}
+			_, _ = fmt.Fprintf(w, `{"project": {"id": 3, "status": %d}}`, status)
+		}))
+	}
+
+	t.Run("should create the membership in an active project", func(t *testing.T) {
+		posted := false
+		ts := newServer(ProjectStatusActive, &posted)
+		defer ts.Close()
+
+		membership, err := NewClient(ts.URL, WithAPIKey("apiKey")).CreateMembershipByProjectID(MembershipDTO{}, 3)
+
+		require.NoError(t, err)
+		assert.True(t, posted)
+		assert.Equal(t, 7, membership.Id)
+	})
+
+	t.Run("should return ProjectStatusError for a closed project", func(t *testing.T) {
+		posted := false
+		ts := newServer(ProjectStatusClosed, &posted)
+		defer ts.Close()
+
+		membership, err := NewClient(ts.URL, WithAPIKey("apiKey")).CreateMembershipByProjectID(MembershipDTO{}, 3)
+
+		require.Error(t, err)
+		assert.Nil(t, membership)
+		assert.False(t, posted)
+		assert.True(t, errors.Is(err, ErrProjectNotActive))
+		var statusErr *ProjectStatusError
+		require.True(t, errors.As(err, &statusErr))
+		assert.Equal(t, &ProjectStatusError{ProjectId: 3, Status: ProjectStatusClosed}, statusErr)
+		assert.Equal(t, "project 3 is closed", err.Error())
+	})
+
+	t.Run("should return AuthError for an archived project", func(t *testing.T) {
+		posted := false
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodPost {
+				posted = true
+			}
+			// Redmine denies access to archived projects instead of returning their status
+			w.WriteHeader(http.StatusForbidden)
+		}))
+		defer ts.Close()
+
+		membership, err := NewClient(ts.URL, WithAPIKey("apiKey")).CreateMembershipByProjectID(MembershipDTO{}, 3)
+
+		assert.Nil(t, membership)
+		assert.False(t, posted)
+		assert.True(t, errors.Is(err, ErrAuth))
+	})
 }
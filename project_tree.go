@@ -0,0 +1,146 @@
+package redmine
+
+import (
+	"context"
+)
+
+// ProjectNode is a project within a ProjectTree.
+type ProjectNode struct {
+	Project Project
+	// Parent contains the node of the parent project or nil for root projects.
+	Parent *ProjectNode
+	// Children contains the nodes of the direct subprojects in the order of the projects the tree was built from.
+	Children []*ProjectNode
+}
+
+// Ancestors returns the nodes of all parent projects, starting with the direct parent and ending with the root
+// project.
+func (n *ProjectNode) Ancestors() []*ProjectNode {
+	var ancestors []*ProjectNode
+	for parent := n.Parent; parent != nil; parent = parent.Parent {
+		ancestors = append(ancestors, parent)
+	}
+	return ancestors
+}
+
+// Descendants returns the nodes of all subprojects in depth-first order, i. e. every project is followed by its own
+// subprojects.
+func (n *ProjectNode) Descendants() []*ProjectNode {
+	var descendants []*ProjectNode
+	for _, child := range n.Children {
+		descendants = append(descendants, child)
+		descendants = append(descendants, child.Descendants()...)
+	}
+	return descendants
+}
+
+// ProjectTree is the hierarchy of a set of projects, see NewProjectTree and Client.ProjectTree.
+type ProjectTree struct {
+	// Roots contains the nodes of all projects without a parent in the tree.
+	Roots []*ProjectNode
+	nodes map[int]*ProjectNode
+}
+
+// NewProjectTree arranges projects by their parent. The parent is taken from Project.Parent as returned by Redmine or,
+// if that is not set, from Project.ParentID. Projects whose parent is not contained in projects, f. e. because it is
+// not visible to the user, become roots of the tree. Projects with a duplicate id are ignored.
+func NewProjectTree(projects []Project) *ProjectTree {
+	tree := &ProjectTree{nodes: make(map[int]*ProjectNode, len(projects))}
+	var ordered []*ProjectNode
+	for _, project := range projects {
+		if _, ok := tree.nodes[project.Id]; ok {
+			continue
+		}
+		node := &ProjectNode{Project: project}
+		tree.nodes[project.Id] = node
+		ordered = append(ordered, node)
+	}
+
+	for _, node := range ordered {
+		parent, ok := tree.nodes[parentProjectId(node.Project)]
+		if !ok || parent.isSelfOrDescendantOf(node) {
+			tree.Roots = append(tree.Roots, node)
+			continue
+		}
+		node.Parent = parent
+		parent.Children = append(parent.Children, node)
+	}
+	return tree
+}
+
+func parentProjectId(project Project) int {
+	if project.Parent != nil {
+		return project.Parent.Id
+	}
+	return project.ParentID.Id
+}
+
+// isSelfOrDescendantOf reports whether n is node or one of its (already linked) subprojects. Linking node as child of
+// such a node would create a cycle.
+func (n *ProjectNode) isSelfOrDescendantOf(node *ProjectNode) bool {
+	for current := n; current != nil; current = current.Parent {
+		if current == node {
+			return true
+		}
+	}
+	return false
+}
+
+// Find returns the node of the project with the given id or nil if the tree does not contain it.
+func (t *ProjectTree) Find(id int) *ProjectNode {
+	return t.nodes[id]
+}
+
+// FindByIdentifier returns the node of the project with the given identifier or nil if the tree does not contain it.
+func (t *ProjectTree) FindByIdentifier(identifier string) *ProjectNode {
+	var found *ProjectNode
+	_ = t.Walk(func(node *ProjectNode, depth int) error {
+		if found == nil && node.Project.Identifier == identifier {
+			found = node
+		}
+		return nil
+	})
+	return found
+}
+
+// Walk calls fn for every node of the tree in depth-first order, starting with the first root. depth is 0 for root
+// projects, 1 for their subprojects and so on. If fn returns an error, Walk stops and returns that error.
+func (t *ProjectTree) Walk(fn func(node *ProjectNode, depth int) error) error {
+	for _, root := range t.Roots {
+		if err := walkProjectNode(root, 0, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func walkProjectNode(node *ProjectNode, depth int, fn func(node *ProjectNode, depth int) error) error {
+	if err := fn(node, depth); err != nil {
+		return err
+	}
+	for _, child := range node.Children {
+		if err := walkProjectNode(child, depth+1, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ProjectTree fetches all projects visible to the user page by page and arranges them by their parent, see
+// NewProjectTree.
+func (c *Client) ProjectTree() (*ProjectTree, error) {
+	return c.ProjectTreeContext(context.Background())
+}
+
+// ProjectTreeContext is like ProjectTree but binds the requests to ctx.
+func (c *Client) ProjectTreeContext(ctx context.Context) (*ProjectTree, error) {
+	var projects []Project
+	it := c.IterateProjects(ctx, c.Limit)
+	for it.Next() {
+		projects = append(projects, it.Item())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return NewProjectTree(projects), nil
+}
@@ -0,0 +1,145 @@
+package redmine
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func projectNodeIds(nodes []*ProjectNode) []int {
+	var ids []int
+	for _, node := range nodes {
+		ids = append(ids, node.Project.Id)
+	}
+	return ids
+}
+
+func TestNewProjectTree(t *testing.T) {
+	projects := []Project{
+		{Id: 1, Identifier: "root"},
+		{Id: 2, Identifier: "child", Parent: &IdName{Id: 1}},
+		{Id: 3, Identifier: "grandchild", Parent: &IdName{Id: 2}},
+		{Id: 4, Identifier: "second-child", ParentID: Id{Id: 1}},
+		{Id: 5, Identifier: "orphan", Parent: &IdName{Id: 42}},
+		{Id: 1, Identifier: "duplicate"},
+	}
+
+	t.Run("should arrange projects by their parent", func(t *testing.T) {
+		tree := NewProjectTree(projects)
+
+		assert.Equal(t, []int{1, 5}, projectNodeIds(tree.Roots))
+		assert.Equal(t, []int{2, 4}, projectNodeIds(tree.Find(1).Children))
+		assert.Equal(t, []int{3}, projectNodeIds(tree.Find(2).Children))
+		assert.Equal(t, "root", tree.Find(1).Project.Identifier)
+		assert.Nil(t, tree.Find(5).Parent)
+	})
+
+	t.Run("should find projects", func(t *testing.T) {
+		tree := NewProjectTree(projects)
+
+		assert.Equal(t, 3, tree.Find(3).Project.Id)
+		assert.Nil(t, tree.Find(42))
+		assert.Equal(t, 4, tree.FindByIdentifier("second-child").Project.Id)
+		assert.Nil(t, tree.FindByIdentifier("missing"))
+	})
+
+	t.Run("should return ancestors and descendants", func(t *testing.T) {
+		tree := NewProjectTree(projects)
+
+		assert.Equal(t, []int{2, 1}, projectNodeIds(tree.Find(3).Ancestors()))
+		assert.Empty(t, tree.Find(1).Ancestors())
+		assert.Equal(t, []int{2, 3, 4}, projectNodeIds(tree.Find(1).Descendants()))
+		assert.Empty(t, tree.Find(3).Descendants())
+	})
+
+	t.Run("should walk the tree depth-first", func(t *testing.T) {
+		tree := NewProjectTree(projects)
+
+		var visited []string
+		err := tree.Walk(func(node *ProjectNode, depth int) error {
+			visited = append(visited, fmt.Sprintf("%d:%d", node.Project.Id, depth))
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []string{"1:0", "2:1", "3:2", "4:1", "5:0"}, visited)
+	})
+
+	t.Run("should stop walking on error", func(t *testing.T) {
+		tree := NewProjectTree(projects)
+		stop := errors.New("stop")
+
+		var visited []int
+		err := tree.Walk(func(node *ProjectNode, depth int) error {
+			visited = append(visited, node.Project.Id)
+			if node.Project.Id == 3 {
+				return stop
+			}
+			return nil
+		})
+
+		assert.Equal(t, stop, err)
+		assert.Equal(t, []int{1, 2, 3}, visited)
+	})
+
+	t.Run("should break cycles", func(t *testing.T) {
+		tree := NewProjectTree([]Project{
+			{Id: 1, Parent: &IdName{Id: 2}},
+			{Id: 2, Parent: &IdName{Id: 1}},
+			{Id: 3, Parent: &IdName{Id: 3}},
+		})
+
+		assert.Equal(t, []int{2, 3}, projectNodeIds(tree.Roots))
+		assert.Equal(t, []int{1}, projectNodeIds(tree.Find(2).Children))
+		assert.Empty(t, tree.Find(3).Children)
+	})
+}
+
+func TestClient_ProjectTree(t *testing.T) {
+	t.Run("should fetch all pages and build the tree", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Query().Get("offset") {
+			case "0":
+				_, _ = fmt.Fprintln(w, `{"projects": [{"id": 1, "name": "root"}, {"id": 2, "name": "child",
+					"parent": {"id": 1, "name": "root"}}], "total_count": 3, "offset": 0, "limit": 2}`)
+			case "2":
+				_, _ = fmt.Fprintln(w, `{"projects": [{"id": 3, "name": "grandchild",
+					"parent": {"id": 2, "name": "child"}}], "total_count": 3, "offset": 2, "limit": 2}`)
+			default:
+				w.WriteHeader(http.StatusBadRequest)
+			}
+		}))
+		defer ts.Close()
+
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"), WithPageSize(2))
+
+		tree, err := sut.ProjectTree()
+
+		require.NoError(t, err)
+		var lines []string
+		_ = tree.Walk(func(node *ProjectNode, depth int) error {
+			lines = append(lines, strings.Repeat("  ", depth)+node.Project.Name)
+			return nil
+		})
+		assert.Equal(t, []string{"root", "  child", "    grandchild"}, lines)
+	})
+
+	t.Run("should return errors of the requests", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer ts.Close()
+
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		tree, err := sut.ProjectTree()
+
+		assert.Nil(t, tree)
+		assert.True(t, errors.Is(err, ErrServer))
+	})
+}
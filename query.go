@@ -0,0 +1,95 @@
+package redmine
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+type queriesResult struct {
+	Queries    []Query `json:"queries"`
+	TotalCount int     `json:"total_count"`
+}
+
+// Query contains a saved issue query.
+//
+// See also: https://www.redmine.org/projects/redmine/wiki/Rest_Queries
+type Query struct {
+	Id       int    `json:"id"`
+	Name     string `json:"name"`
+	IsPublic bool   `json:"is_public"`
+	// ProjectId contains the id of the project the query belongs to or 0 if the query is available in all projects.
+	ProjectId int `json:"project_id"`
+}
+
+// Queries returns all saved queries visible to the user.
+func (c *Client) Queries() ([]Query, error) {
+	return c.QueriesContext(context.Background())
+}
+
+// QueriesContext is like Queries but binds the requests to ctx.
+func (c *Client) QueriesContext(ctx context.Context) ([]Query, error) {
+	var queries []Query
+	it := newPaginator(ctx, c.Limit, func(ctx context.Context, pagination ...string) (int, int, error) {
+		var r queriesResult
+		err := c.get(ctx, c.pathWithParameters("/queries.json", pagination...), &r)
+		if err != nil {
+			return 0, 0, err
+		}
+		queries = append(queries, r.Queries...)
+		return len(r.Queries), r.TotalCount, nil
+	})
+	for it.Next() {
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return queries, nil
+}
+
+// QueryByName returns the saved query with the given name which is usable in the project with the id projectId. A
+// query of the project takes precedence over a global query with the same name. If no such query exists, an error
+// matching ErrNotFound is returned.
+func (c *Client) QueryByName(projectId int, name string) (*Query, error) {
+	return c.QueryByNameContext(context.Background(), projectId, name)
+}
+
+// QueryByNameContext is like QueryByName but binds the requests to ctx.
+func (c *Client) QueryByNameContext(ctx context.Context, projectId int, name string) (*Query, error) {
+	queries, err := c.QueriesContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var global *Query
+	for i, query := range queries {
+		if query.Name != name {
+			continue
+		}
+		if query.ProjectId == projectId {
+			return &queries[i], nil
+		}
+		if query.ProjectId == 0 && global == nil {
+			global = &queries[i]
+		}
+	}
+	if global == nil {
+		return nil, fmt.Errorf("saved query %q of project %d: %w", name, projectId, ErrNotFound)
+	}
+	return global, nil
+}
+
+// IssuesByQueryName returns all issues of the project with the id projectId which match the saved query with the given
+// name, see QueryByName.
+func (c *Client) IssuesByQueryName(projectId int, name string) ([]Issue, error) {
+	return c.IssuesByQueryNameContext(context.Background(), projectId, name)
+}
+
+// IssuesByQueryNameContext is like IssuesByQueryName but binds the requests to ctx.
+func (c *Client) IssuesByQueryNameContext(ctx context.Context, projectId int, name string) ([]Issue, error) {
+	query, err := c.QueryByNameContext(ctx, projectId, name)
+	if err != nil {
+		return nil, err
+	}
+	return getIssues(ctx, c, "query_id="+strconv.Itoa(query.Id), "project_id="+strconv.Itoa(projectId))
+}
@@ -0,0 +1,97 @@
+package redmine
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+const queriesJSON = `{
+  "queries": [
+    {"id": 1, "name": "Open bugs", "is_public": true},
+    {"id": 2, "name": "Open bugs", "is_public": true, "project_id": 3},
+    {"id": 3, "name": "My issues", "is_public": false}
+  ],
+  "total_count": 3,
+  "offset": 0,
+  "limit": 25
+}`
+
+func TestClient_Queries(t *testing.T) {
+	t.Run("should parse saved queries", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/queries.json", r.URL.Path)
+			_, _ = fmt.Fprintln(w, queriesJSON)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		actual, err := sut.Queries()
+
+		require.NoError(t, err)
+		assert.Equal(t, []Query{
+			{Id: 1, Name: "Open bugs", IsPublic: true},
+			{Id: 2, Name: "Open bugs", IsPublic: true, ProjectId: 3},
+			{Id: 3, Name: "My issues"},
+		}, actual)
+	})
+}
+
+func TestClient_QueryByName(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = fmt.Fprintln(w, queriesJSON)
+	}))
+	defer ts.Close()
+	sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+	t.Run("should prefer the query of the project", func(t *testing.T) {
+		actual, err := sut.QueryByName(3, "Open bugs")
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, actual.Id)
+	})
+
+	t.Run("should fall back to a global query", func(t *testing.T) {
+		actual, err := sut.QueryByName(4, "Open bugs")
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, actual.Id)
+	})
+
+	t.Run("should return not found for unknown names", func(t *testing.T) {
+		actual, err := sut.QueryByName(3, "Closed bugs")
+
+		assert.Nil(t, actual)
+		assert.True(t, errors.Is(err, ErrNotFound))
+		assert.Contains(t, err.Error(), `"Closed bugs"`)
+	})
+}
+
+func TestClient_IssuesByQueryName(t *testing.T) {
+	t.Run("should run the saved query in the project", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/queries.json":
+				_, _ = fmt.Fprintln(w, queriesJSON)
+			case "/issues.json":
+				assert.Equal(t, "2", r.URL.Query().Get("query_id"))
+				assert.Equal(t, "3", r.URL.Query().Get("project_id"))
+				_, _ = fmt.Fprintln(w, `{"issues": [{"id": 7}], "total_count": 1}`)
+			default:
+				t.Errorf("unexpected request %s", r.URL)
+			}
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		actual, err := sut.IssuesByQueryName(3, "Open bugs")
+
+		require.NoError(t, err)
+		require.Len(t, actual, 1)
+		assert.Equal(t, 7, actual[0].Id)
+	})
+}
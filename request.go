@@ -0,0 +1,188 @@
+package redmine
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// redacted replaces credentials in URLs which are surfaced in errors.
+const redacted = "xxxxx"
+
+// Doer sends a single HTTP request and returns its response. *http.Client satisfies this interface.
+type Doer interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+// DoerFunc adapts an ordinary function to the Doer interface.
+type DoerFunc func(req *http.Request) (*http.Response, error)
+
+// Do calls f(req).
+func (f DoerFunc) Do(req *http.Request) (*http.Response, error) {
+	return f(req)
+}
+
+// Middleware wraps the Doer which sends the requests of a client, f. e. to add logging, tracing or additional
+// headers to every request.
+type Middleware func(next Doer) Doer
+
+// Use registers middlewares for all subsequent requests of the client. The middleware registered first is the
+// outermost one and thus sees a request first. Use is not safe for concurrent use and should be called before the
+// client is shared between goroutines.
+func (c *Client) Use(middlewares ...Middleware) {
+	c.middlewares = append(c.middlewares, middlewares...)
+}
+
+func (c *Client) get(ctx context.Context, path string, result interface{}) error {
+	return c.do(ctx, http.MethodGet, path, nil, result)
+}
+
+func (c *Client) post(ctx context.Context, path string, body interface{}, result interface{}) error {
+	return c.do(ctx, http.MethodPost, path, body, result)
+}
+
+func (c *Client) put(ctx context.Context, path string, body interface{}, result interface{}) error {
+	return c.do(ctx, http.MethodPut, path, body, result)
+}
+
+func (c *Client) patch(ctx context.Context, path string, body interface{}, result interface{}) error {
+	return c.do(ctx, http.MethodPatch, path, body, result)
+}
+
+func (c *Client) delete(ctx context.Context, path string) error {
+	return c.do(ctx, http.MethodDelete, path, nil, nil)
+}
+
+// do marshals body (if any) as JSON and executes the request.
+func (c *Client) do(ctx context.Context, method, path string, body interface{}, result interface{}) error {
+	if body == nil {
+		return c.doRaw(ctx, method, path, nil, "", result)
+	}
+
+	s, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	return c.doRaw(ctx, method, path, bytes.NewReader(s), "application/json", result)
+}
+
+// doRaw is the single place where requests against the Redmine API are built and sent. Every response with a 2xx
+// status code is considered successful and its body is decoded into result unless result is nil or the body is empty.
+// If result is an io.Writer, the body is copied into it as is. All other responses are converted into the typed errors
+// of this package.
+func (c *Client) doRaw(ctx context.Context, method, path string, body io.Reader, contentType string, result interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, method, c.endpoint+path, body)
+	if err != nil {
+		return redactError(err)
+	}
+	if sized, ok := body.(interface{ ContentLength() int64 }); ok {
+		// net/http only knows the length of in-memory readers; -1 sends the body chunked
+		req.ContentLength = sized.ContentLength()
+	}
+	if seeker, ok := body.(io.Seeker); ok && req.GetBody == nil {
+		// net/http can only rewind in-memory readers; seeking back allows retries of streamed bodies like files
+		if start, err := seeker.Seek(0, io.SeekCurrent); err == nil {
+			req.GetBody = func() (io.ReadCloser, error) {
+				if _, err := seeker.Seek(start, io.SeekStart); err != nil {
+					return nil, err
+				}
+				return ioutil.NopCloser(body), nil
+			}
+		}
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	c.authenticate(req)
+
+	if err := c.inFlight.acquire(ctx); err != nil {
+		return err
+	}
+	defer c.inFlight.release()
+
+	res, err := c.sendWithRetries(req)
+	if err != nil {
+		err = redactError(err)
+		c.logf("%s %s failed: %v", method, redactURL(req.URL.String()), err)
+		return err
+	}
+	defer res.Body.Close()
+	c.logf("%s %s: %s", method, redactURL(req.URL.String()), res.Status)
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		return errorFromResponse(res)
+	}
+	if result == nil {
+		return nil
+	}
+	if w, ok := result.(io.Writer); ok {
+		_, err = io.Copy(w, res.Body)
+		return err
+	}
+
+	err = json.NewDecoder(res.Body).Decode(result)
+	if err == io.EOF {
+		return nil
+	}
+	return err
+}
+
+// send passes req through all registered middlewares to the underlying http.Client.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	var doer Doer = c.Client
+	for i := len(c.middlewares) - 1; i >= 0; i-- {
+		doer = c.middlewares[i](doer)
+	}
+	return doer.Do(req)
+}
+
+func (c *Client) logf(format string, v ...interface{}) {
+	if c.logger != nil {
+		c.logger.Printf(format, v...)
+	}
+}
+
+func (c *Client) authenticate(req *http.Request) {
+	if c.auth != nil {
+		c.auth.Authenticate(req)
+	}
+	if login, ok := asUserFromContext(req.Context()); ok {
+		req.Header.Set("X-Redmine-Switch-User", login)
+	}
+}
+
+// redactError removes credentials from the URL of errors returned by net/http, f. e.
+// `Get "https://redmine.example.com/issues.json?key=abc": dial tcp ...`, so that they do not leak into logs.
+func redactError(err error) error {
+	urlErr, ok := err.(*url.Error)
+	if !ok {
+		return err
+	}
+	return &url.Error{Op: urlErr.Op, URL: redactURL(urlErr.URL), Err: urlErr.Err}
+}
+
+// redactURL masks the value of the "key" query parameter and the password of the user info of rawURL.
+func redactURL(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	if u.User != nil {
+		if _, hasPassword := u.User.Password(); hasPassword {
+			u.User = url.UserPassword(u.User.Username(), redacted)
+		}
+	}
+	query := u.Query()
+	if _, hasKey := query["key"]; hasKey {
+		query.Set("key", redacted)
+		u.RawQuery = query.Encode()
+	}
+	return u.String()
+}
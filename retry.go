@@ -0,0 +1,187 @@
+package redmine
+
+import (
+	"errors"
+	"io"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// RetryPolicy controls how requests are repeated after transient failures, i. e. network errors like refused or reset
+// connections and the HTTP status codes 429, 502, 503 and 504. Errors which persist, like invalid TLS certificates,
+// are never retried. GET, PUT, PATCH and DELETE requests are retried; POST requests are only retried if RetryPOST is
+// set because repeating f. e. CreateIssue may create duplicates.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts including the first one. Values below 2 disable retries.
+	MaxAttempts int
+	// MinBackoff is the wait before the first retry. The wait doubles with every further retry and is randomized by
+	// up to 50% to avoid many clients retrying in lockstep.
+	MinBackoff time.Duration
+	// MaxBackoff caps the wait between two attempts including waits requested by a Retry-After response header. 0
+	// disables the cap.
+	MaxBackoff time.Duration
+	// RetryPOST enables retries of POST requests like CreateIssue or Upload.
+	RetryPOST bool
+}
+
+// DefaultRetryPolicy returns a policy which makes up to four attempts and waits between half a second and 30 seconds
+// between them.
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 4,
+		MinBackoff:  500 * time.Millisecond,
+		MaxBackoff:  30 * time.Second,
+	}
+}
+
+// WithRetryPolicy repeats requests after transient failures according to policy. Clients without this option never
+// retry requests.
+func WithRetryPolicy(policy RetryPolicy) Option {
+	return func(o *options) {
+		o.retryPolicy = policy
+	}
+}
+
+func (p RetryPolicy) maxAttempts(req *http.Request) int {
+	if p.MaxAttempts < 2 {
+		return 1
+	}
+	if req.Method == http.MethodPost && !p.RetryPOST {
+		return 1
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		// the body was already consumed by the first attempt and cannot be sent again
+		return 1
+	}
+	return p.MaxAttempts
+}
+
+func isRetryable(req *http.Request, res *http.Response, err error) bool {
+	if err != nil {
+		return req.Context().Err() == nil && isTransientError(err)
+	}
+	switch res.StatusCode {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// isTransientError reports whether err is a network failure which may disappear on the next attempt. Other errors of
+// the transport like unsupported URL schemes or TLS certificate failures would fail again.
+func isTransientError(err error) bool {
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) {
+		return true
+	}
+	// *url.Error implements net.Error itself, so only the error it wraps tells whether the network failed
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		err = urlErr.Err
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+// backoff returns the wait before the given retry (starting with 1).
+func (p RetryPolicy) backoff(retry int, res *http.Response) time.Duration {
+	if res != nil {
+		if wait, ok := parseRetryAfter(res.Header.Get("Retry-After")); ok {
+			return p.capBackoff(wait)
+		}
+	}
+
+	wait := p.MinBackoff
+	for i := 1; i < retry && (p.MaxBackoff <= 0 || wait < p.MaxBackoff) && wait <= math.MaxInt64/2; i++ {
+		wait *= 2
+	}
+	wait = p.capBackoff(wait)
+	if wait <= 0 {
+		return 0
+	}
+	return wait/2 + time.Duration(rand.Int63n(int64(wait/2)+1))
+}
+
+func (p RetryPolicy) capBackoff(wait time.Duration) time.Duration {
+	if p.MaxBackoff > 0 && wait > p.MaxBackoff {
+		return p.MaxBackoff
+	}
+	return wait
+}
+
+// parseRetryAfter supports both forms of the Retry-After header: delay in seconds and HTTP date.
+func parseRetryAfter(value string) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		wait := time.Until(date)
+		if wait < 0 {
+			wait = 0
+		}
+		return wait, true
+	}
+	return 0, false
+}
+
+// sendWithRetries sends req and repeats it according to the retry policy of the client.
+func (c *Client) sendWithRetries(req *http.Request) (*http.Response, error) {
+	maxAttempts := c.retryPolicy.maxAttempts(req)
+	for attempt := 1; ; attempt++ {
+		if err := c.rateLimiter.wait(req.Context()); err != nil {
+			return nil, err
+		}
+
+		res, err := c.send(req)
+		if attempt >= maxAttempts || !isRetryable(req, res, err) {
+			return res, err
+		}
+
+		wait := c.retryPolicy.backoff(attempt, res)
+		if res != nil {
+			_, _ = io.Copy(ioutil.Discard, res.Body)
+			res.Body.Close()
+			c.logf("%s %s: %s, retrying in %s", req.Method, redactURL(req.URL.String()), res.Status, wait)
+		} else {
+			c.logf("%s %s failed: %v, retrying in %s", req.Method, redactURL(req.URL.String()), redactError(err), wait)
+		}
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-req.Context().Done():
+			timer.Stop()
+			return nil, req.Context().Err()
+		case <-timer.C:
+		}
+
+		req, err = rewindRequest(req)
+		if err != nil {
+			return nil, err
+		}
+	}
+}
+
+func rewindRequest(req *http.Request) (*http.Request, error) {
+	next := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil, err
+		}
+		next.Body = body
+	}
+	return next, nil
+}
@@ -0,0 +1,238 @@
+package redmine
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func testRetryPolicy() RetryPolicy {
+	return RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+}
+
+func TestClient_sendWithRetries(t *testing.T) {
+	t.Run("should retry GET requests after transient failures", func(t *testing.T) {
+		requests := 0
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if requests < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			_, _ = fmt.Fprintln(w, `{"issue": {"id": 1}}`)
+		}))
+		defer ts.Close()
+
+		sut := NewClient(ts.URL, WithRetryPolicy(testRetryPolicy()))
+
+		actual, err := sut.Issue(1)
+
+		require.NoError(t, err)
+		assert.Equal(t, 1, actual.Id)
+		assert.Equal(t, 3, requests)
+	})
+
+	t.Run("should give up after max attempts", func(t *testing.T) {
+		requests := 0
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer ts.Close()
+
+		sut := NewClient(ts.URL, WithRetryPolicy(testRetryPolicy()))
+
+		err := sut.DeleteIssue(1)
+
+		assert.True(t, errors.Is(err, ErrServer))
+		assert.Equal(t, 3, requests)
+	})
+
+	t.Run("should not retry other errors", func(t *testing.T) {
+		requests := 0
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer ts.Close()
+
+		sut := NewClient(ts.URL, WithRetryPolicy(testRetryPolicy()))
+
+		_, err := sut.Issue(1)
+
+		assert.True(t, errors.Is(err, ErrServer))
+		assert.Equal(t, 1, requests)
+	})
+
+	t.Run("should not retry POST requests by default", func(t *testing.T) {
+		requests := 0
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer ts.Close()
+
+		sut := NewClient(ts.URL, WithRetryPolicy(testRetryPolicy()))
+
+		_, err := sut.CreateIssue(Issue{Subject: "hello"})
+
+		assert.Error(t, err)
+		assert.Equal(t, 1, requests)
+	})
+
+	t.Run("should retry POST requests with the same body if enabled", func(t *testing.T) {
+		var bodies []string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			bodies = append(bodies, string(body))
+			if len(bodies) == 1 {
+				w.WriteHeader(http.StatusGatewayTimeout)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			_, _ = fmt.Fprintln(w, `{"issue": {"id": 2}}`)
+		}))
+		defer ts.Close()
+		policy := testRetryPolicy()
+		policy.RetryPOST = true
+
+		sut := NewClient(ts.URL, WithRetryPolicy(policy))
+
+		actual, err := sut.CreateIssue(Issue{Subject: "hello"})
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, actual.Id)
+		require.Len(t, bodies, 2)
+		assert.Equal(t, bodies[0], bodies[1])
+		assert.Contains(t, bodies[1], `"subject":"hello"`)
+	})
+
+	t.Run("should retry PATCH requests", func(t *testing.T) {
+		requests := 0
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests++
+			if requests == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer ts.Close()
+
+		sut := NewClient(ts.URL, WithRetryPolicy(testRetryPolicy()))
+
+		err := sut.UpdateAttachment(Attachment{Id: 1, Description: "hello"})
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, requests)
+	})
+
+	countAttempts := func(sut *Client) *int {
+		attempts := 0
+		sut.Use(func(next Doer) Doer {
+			return DoerFunc(func(req *http.Request) (*http.Response, error) {
+				attempts++
+				return next.Do(req)
+			})
+		})
+		return &attempts
+	}
+
+	t.Run("should retry refused connections", func(t *testing.T) {
+		ts := httptest.NewServer(http.NotFoundHandler())
+		ts.Close()
+		sut := NewClient(ts.URL, WithRetryPolicy(testRetryPolicy()))
+		attempts := countAttempts(sut)
+
+		_, err := sut.Issue(1)
+
+		assert.Error(t, err)
+		assert.Equal(t, 3, *attempts)
+	})
+
+	t.Run("should not retry TLS certificate failures", func(t *testing.T) {
+		ts := httptest.NewTLSServer(http.NotFoundHandler())
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithRetryPolicy(testRetryPolicy()))
+		attempts := countAttempts(sut)
+
+		_, err := sut.Issue(1)
+
+		assert.Error(t, err)
+		assert.Equal(t, 1, *attempts)
+	})
+
+	t.Run("should not retry unsupported URL schemes", func(t *testing.T) {
+		sut := NewClient("ftp://redmine.example.com", WithRetryPolicy(testRetryPolicy()))
+		attempts := countAttempts(sut)
+
+		_, err := sut.Issue(1)
+
+		assert.Error(t, err)
+		assert.Equal(t, 1, *attempts)
+	})
+
+	t.Run("should stop waiting when the context is cancelled", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Retry-After", "60")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}))
+		defer ts.Close()
+		policy := testRetryPolicy()
+		policy.MaxBackoff = time.Minute
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		sut := NewClient(ts.URL, WithRetryPolicy(policy))
+
+		_, err := sut.IssueContext(ctx, 1)
+
+		assert.True(t, errors.Is(err, context.DeadlineExceeded))
+	})
+}
+
+func TestRetryPolicy_backoff(t *testing.T) {
+	sut := RetryPolicy{MaxAttempts: 10, MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+
+	t.Run("should grow exponentially with jitter", func(t *testing.T) {
+		for retry, max := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 400 * time.Millisecond, 6: time.Second} {
+			actual := sut.backoff(retry, nil)
+
+			assert.True(t, actual >= max/2 && actual <= max, "retry %d: %s", retry, actual)
+		}
+	})
+
+	t.Run("should grow exponentially without maximum backoff", func(t *testing.T) {
+		uncapped := RetryPolicy{MaxAttempts: 5, MinBackoff: time.Second}
+
+		for retry, max := range map[int]time.Duration{1: time.Second, 2: 2 * time.Second, 3: 4 * time.Second, 4: 8 * time.Second} {
+			actual := uncapped.backoff(retry, nil)
+
+			assert.True(t, actual >= max/2 && actual <= max, "retry %d: %s", retry, actual)
+		}
+		assert.True(t, uncapped.backoff(100, nil) > 0)
+	})
+
+	t.Run("should honor Retry-After in seconds up to the maximum backoff", func(t *testing.T) {
+		res := &http.Response{Header: http.Header{}}
+		res.Header.Set("Retry-After", "1")
+		assert.Equal(t, time.Second, sut.backoff(1, res))
+
+		res.Header.Set("Retry-After", "120")
+		assert.Equal(t, time.Second, sut.backoff(1, res))
+	})
+
+	t.Run("should honor Retry-After as HTTP date", func(t *testing.T) {
+		res := &http.Response{Header: http.Header{}}
+		res.Header.Set("Retry-After", time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat))
+
+		assert.Equal(t, time.Duration(0), sut.backoff(1, res))
+	})
+}
@@ -1,9 +1,7 @@
 package redmine
 
 import (
-	"encoding/json"
-	"errors"
-	"strings"
+	"context"
 )
 
 type rolesResult struct {
@@ -11,23 +9,12 @@ type rolesResult struct {
 }
 
 func (c *Client) Roles() ([]IdName, error) {
-	res, err := c.Get(c.endpoint + "/roles.json?key=" + c.apikey + c.getPaginationClause())
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
+	return c.RolesContext(context.Background())
+}
 
-	decoder := json.NewDecoder(res.Body)
+func (c *Client) RolesContext(ctx context.Context) ([]IdName, error) {
 	var r rolesResult
-	if res.StatusCode != 200 {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	} else {
-		err = decoder.Decode(&r)
-	}
+	err := c.get(ctx, c.pathWithParameters("/roles.json", c.getPaginationClause()), &r)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,136 @@
+package redmine
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+type searchResult struct {
+	Results    []SearchResult `json:"results"`
+	TotalCount int            `json:"total_count"`
+}
+
+// SearchResult is a single hit of Search.
+//
+// See also: https://www.redmine.org/projects/redmine/wiki/Rest_Search
+type SearchResult struct {
+	Id    int    `json:"id"`
+	Title string `json:"title"`
+	// Type contains the kind of the hit, f. e. "issue", "issue closed", "wiki-page", "news" or "changeset".
+	Type        string   `json:"type"`
+	URL         string   `json:"url"`
+	Description string   `json:"description"`
+	Datetime    DateTime `json:"datetime"`
+}
+
+const (
+	SearchScopeAll         string = "all"
+	SearchScopeMyProjects  string = "my_projects"
+	SearchScopeSubprojects string = "subprojects"
+)
+
+// SearchOptions contains the optional parameters of Search. If none of the resource types like Issues or WikiPages
+// is selected, all types are searched.
+type SearchOptions struct {
+	// ProjectId restricts the search to the project with this id. 0 searches all projects.
+	ProjectId int
+	// Scope is one of SearchScopeAll, SearchScopeMyProjects and SearchScopeSubprojects.
+	Scope string
+	// AllWords only finds resources which contain all words of the query.
+	AllWords bool
+	// TitlesOnly searches the titles only.
+	TitlesOnly bool
+	// OpenIssues only finds open issues.
+	OpenIssues bool
+
+	Issues     bool
+	News       bool
+	Documents  bool
+	Changesets bool
+	WikiPages  bool
+	Messages   bool
+	Projects   bool
+}
+
+func (o SearchOptions) path() string {
+	if o.ProjectId > 0 {
+		return "/projects/" + strconv.Itoa(o.ProjectId) + "/search.json"
+	}
+	return "/search.json"
+}
+
+func (o SearchOptions) parameters(query string) []string {
+	parameters := []string{"q=" + url.QueryEscape(query)}
+	if o.Scope != "" {
+		parameters = append(parameters, "scope="+url.QueryEscape(o.Scope))
+	}
+	flags := []struct {
+		name string
+		set  bool
+	}{
+		{"all_words", o.AllWords},
+		{"titles_only", o.TitlesOnly},
+		{"open_issues", o.OpenIssues},
+		{"issues", o.Issues},
+		{"news", o.News},
+		{"documents", o.Documents},
+		{"changesets", o.Changesets},
+		{"wiki_pages", o.WikiPages},
+		{"messages", o.Messages},
+		{"projects", o.Projects},
+	}
+	for _, flag := range flags {
+		if flag.set {
+			parameters = append(parameters, flag.name+"=1")
+		}
+	}
+	return parameters
+}
+
+// Search returns all resources matching query.
+func (c *Client) Search(query string, options SearchOptions) ([]SearchResult, error) {
+	return c.SearchContext(context.Background(), query, options)
+}
+
+// SearchContext is like Search but binds the requests to ctx.
+func (c *Client) SearchContext(ctx context.Context, query string, options SearchOptions) ([]SearchResult, error) {
+	var results []SearchResult
+	it := c.IterateSearch(ctx, query, options, c.Limit)
+	for it.Next() {
+		results = append(results, it.Item())
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// SearchResultIterator iterates over the hits of a search, see Paginator.
+type SearchResultIterator struct {
+	*Paginator
+	page []SearchResult
+}
+
+// Item returns the current hit.
+func (it *SearchResultIterator) Item() SearchResult {
+	return it.page[it.index]
+}
+
+// IterateSearch returns an iterator over all resources matching query. Every request fetches up to pageSize hits;
+// values below 1 use Redmine's default page size.
+func (c *Client) IterateSearch(ctx context.Context, query string, options SearchOptions, pageSize int) *SearchResultIterator {
+	parameters := options.parameters(query)
+
+	it := &SearchResultIterator{}
+	it.Paginator = newPaginator(ctx, pageSize, func(ctx context.Context, pagination ...string) (int, int, error) {
+		var r searchResult
+		err := c.get(ctx, c.pathWithParameters(options.path(), append(pagination, parameters...)...), &r)
+		if err != nil {
+			return 0, 0, err
+		}
+		it.page = r.Results
+		return len(r.Results), r.TotalCount, nil
+	})
+	return it
+}
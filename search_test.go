@@ -0,0 +1,62 @@
+package redmine
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_Search(t *testing.T) {
+	t.Run("should send the options and fetch all pages", func(t *testing.T) {
+		var queries []string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/projects/1/search.json", r.URL.Path)
+			queries = append(queries, r.URL.RawQuery)
+			if r.URL.Query().Get("offset") == "0" {
+				_, _ = fmt.Fprintln(w, `{"results": [{"id": 5, "title": "Bug #5 (New): Login fails", "type": "issue", "url": "https://redmine.example.com/issues/5", "description": "Login fails with & in password", "datetime": "2021-03-01T10:00:00Z"}], "total_count": 2}`)
+				return
+			}
+			_, _ = fmt.Fprintln(w, `{"results": [{"id": 2, "title": "Wiki: Login", "type": "wiki-page", "url": "https://redmine.example.com/projects/example/wiki/Login"}], "total_count": 2}`)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"), WithPageSize(1))
+
+		actual, err := sut.Search("login & password", SearchOptions{
+			ProjectId:  1,
+			Scope:      SearchScopeSubprojects,
+			TitlesOnly: true,
+			Issues:     true,
+			WikiPages:  true,
+		})
+
+		require.NoError(t, err)
+		require.Len(t, actual, 2)
+		assert.Equal(t, "Bug #5 (New): Login fails", actual[0].Title)
+		assert.Equal(t, "issue", actual[0].Type)
+		assert.Equal(t, "https://redmine.example.com/issues/5", actual[0].URL)
+		assert.Equal(t, "2021-03-01T10:00:00Z", actual[0].Datetime.String())
+		assert.Equal(t, "wiki-page", actual[1].Type)
+		assert.Equal(t, []string{
+			"offset=0&limit=1&q=login+%26+password&scope=subprojects&titles_only=1&issues=1&wiki_pages=1",
+			"offset=1&limit=1&q=login+%26+password&scope=subprojects&titles_only=1&issues=1&wiki_pages=1",
+		}, queries)
+	})
+
+	t.Run("should search all projects without further options", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/search.json", r.URL.Path)
+			assert.Equal(t, "offset=0&q=redmine", r.URL.RawQuery)
+			_, _ = fmt.Fprintln(w, `{"results": [], "total_count": 0}`)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		actual, err := sut.Search("redmine", SearchOptions{})
+
+		require.NoError(t, err)
+		assert.Empty(t, actual)
+	})
+}
@@ -1,15 +1,13 @@
 package redmine
 
 import (
-	"encoding/json"
-	"errors"
-	"net/http"
+	"context"
 	"strconv"
-	"strings"
 )
 
 type timeEntriesResult struct {
 	TimeEntries []TimeEntry `json:"time_entries"`
+	TotalCount  int         `json:"total_count"`
 }
 
 type timeEntryResult struct {
@@ -28,97 +26,103 @@ type TimeEntry struct {
 	Activity     IdName         `json:"activity"`
 	Hours        float32        `json:"hours"`
 	Comments     string         `json:"comments"`
-	SpentOn      string         `json:"spent_on"`
-	CreatedOn    string         `json:"created_on"`
-	UpdatedOn    string         `json:"updated_on"`
+	SpentOn      Date           `json:"spent_on"`
+	CreatedOn    DateTime       `json:"created_on"`
+	UpdatedOn    DateTime       `json:"updated_on"`
 	CustomFields []*CustomField `json:"custom_fields,omitempty"`
 }
 
 // TimeEntriesWithFilter send query and return parsed result
 func (c *Client) TimeEntriesWithFilter(filter Filter) ([]TimeEntry, error) {
-	uri, err := c.URLWithFilter("/time_entries.json", filter)
-	if err != nil {
-		return nil, err
-	}
-	req, err := http.NewRequest("GET", uri, nil)
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Add("X-Redmine-API-Key", c.apikey)
-	res, err := c.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
+	return c.TimeEntriesWithFilterContext(context.Background(), filter)
+}
 
-	decoder := json.NewDecoder(res.Body)
+// TimeEntriesWithFilterContext is like TimeEntriesWithFilter but binds the request to ctx.
+func (c *Client) TimeEntriesWithFilterContext(ctx context.Context, filter Filter) ([]TimeEntry, error) {
 	var r timeEntriesResult
-	if res.StatusCode == 404 {
-		return nil, errors.New("Not Found")
-	}
-	if res.StatusCode != 200 {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	} else {
-		err = decoder.Decode(&r)
-	}
+	err := c.get(ctx, c.pathWithParameters("/time_entries.json", c.getPaginationClause(), filter.ToURLParams()), &r)
 	if err != nil {
 		return nil, err
 	}
 	return r.TimeEntries, nil
 }
 
-func (c *Client) TimeEntries(projectId int) ([]TimeEntry, error) {
-	res, err := c.Get(c.endpoint + "/projects/" + strconv.Itoa(projectId) + "/time_entries.json?key=" + c.apikey + c.getPaginationClause())
+// TimeEntriesWithFilterParallel fetches all time entries matching filter. The pages after the first one are fetched
+// with up to workers concurrent requests, the time entries are returned in page order. The page size is configured
+// with WithPageSize; Redmine allows up to 100 time entries per page by default.
+func (c *Client) TimeEntriesWithFilterParallel(filter Filter, workers int) ([]TimeEntry, error) {
+	return c.TimeEntriesWithFilterParallelContext(context.Background(), filter, workers)
+}
+
+// TimeEntriesWithFilterParallelContext is like TimeEntriesWithFilterParallel but binds the requests to ctx. The first
+// failing request cancels all others.
+func (c *Client) TimeEntriesWithFilterParallelContext(ctx context.Context, filter Filter, workers int) ([]TimeEntry, error) {
+	pages, err := fetchPagesInParallel(ctx, workers, c.Limit, func(ctx context.Context, pagination ...string) (interface{}, int, int, error) {
+		var r timeEntriesResult
+		err := c.get(ctx, c.pathWithParameters("/time_entries.json", append(pagination, filter.ToURLParams())...), &r)
+		if err != nil {
+			return nil, 0, 0, err
+		}
+		return r.TimeEntries, len(r.TimeEntries), r.TotalCount, nil
+	})
 	if err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
 
-	decoder := json.NewDecoder(res.Body)
-	var r timeEntriesResult
-	if res.StatusCode == 404 {
-		return nil, errors.New("Not Found")
-	}
-	if res.StatusCode != 200 {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	} else {
-		err = decoder.Decode(&r)
+	var timeEntries []TimeEntry
+	for _, page := range pages {
+		timeEntries = append(timeEntries, page.([]TimeEntry)...)
 	}
+	return timeEntries, nil
+}
+
+func (c *Client) TimeEntries(projectId int) ([]TimeEntry, error) {
+	return c.TimeEntriesContext(context.Background(), projectId)
+}
+
+func (c *Client) TimeEntriesContext(ctx context.Context, projectId int) ([]TimeEntry, error) {
+	var r timeEntriesResult
+	err := c.get(ctx, c.pathWithParameters("/projects/"+strconv.Itoa(projectId)+"/time_entries.json", c.getPaginationClause()), &r)
 	if err != nil {
 		return nil, err
 	}
 	return r.TimeEntries, nil
 }
 
+// TimeEntryIterator iterates over the time entries of a list request, see Paginator.
+type TimeEntryIterator struct {
+	*Paginator
+	page []TimeEntry
+}
+
+// Item returns the current time entry.
+func (it *TimeEntryIterator) Item() TimeEntry {
+	return it.page[it.index]
+}
+
+// IterateTimeEntries returns an iterator over all time entries matching filter. Every request fetches up to pageSize
+// time entries; values below 1 use Redmine's default page size.
+func (c *Client) IterateTimeEntries(ctx context.Context, filter Filter, pageSize int) *TimeEntryIterator {
+	it := &TimeEntryIterator{}
+	it.Paginator = newPaginator(ctx, pageSize, func(ctx context.Context, pagination ...string) (int, int, error) {
+		var r timeEntriesResult
+		err := c.get(ctx, c.pathWithParameters("/time_entries.json", append(pagination, filter.ToURLParams())...), &r)
+		if err != nil {
+			return 0, 0, err
+		}
+		it.page = r.TimeEntries
+		return len(r.TimeEntries), r.TotalCount, nil
+	})
+	return it
+}
+
 func (c *Client) TimeEntry(id int) (*TimeEntry, error) {
-	res, err := c.Get(c.endpoint + "/time_entries/" + strconv.Itoa(id) + ".json?key=" + c.apikey)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
+	return c.TimeEntryContext(context.Background(), id)
+}
 
-	decoder := json.NewDecoder(res.Body)
+func (c *Client) TimeEntryContext(ctx context.Context, id int) (*TimeEntry, error) {
 	var r timeEntryResult
-	if res.StatusCode == 404 {
-		return nil, errors.New("Not Found")
-	}
-	if res.StatusCode != 200 {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	} else {
-		err = decoder.Decode(&r)
-	}
+	err := c.get(ctx, "/time_entries/"+strconv.Itoa(id)+".json", &r)
 	if err != nil {
 		return nil, err
 	}
@@ -126,34 +130,12 @@ func (c *Client) TimeEntry(id int) (*TimeEntry, error) {
 }
 
 func (c *Client) CreateTimeEntry(timeEntry TimeEntry) (*TimeEntry, error) {
-	var ir timeEntryRequest
-	ir.TimeEntry = timeEntry
-	s, err := json.Marshal(ir)
-	if err != nil {
-		return nil, err
-	}
-	req, err := http.NewRequest("POST", c.endpoint+"/time_entries.json?key="+c.apikey, strings.NewReader(string(s)))
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	res, err := c.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
+	return c.CreateTimeEntryContext(context.Background(), timeEntry)
+}
 
-	decoder := json.NewDecoder(res.Body)
+func (c *Client) CreateTimeEntryContext(ctx context.Context, timeEntry TimeEntry) (*TimeEntry, error) {
 	var r timeEntryResult
-	if res.StatusCode != 201 {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	} else {
-		err = decoder.Decode(&r)
-	}
+	err := c.post(ctx, "/time_entries.json", timeEntryRequest{TimeEntry: timeEntry}, &r)
 	if err != nil {
 		return nil, err
 	}
@@ -161,63 +143,17 @@ func (c *Client) CreateTimeEntry(timeEntry TimeEntry) (*TimeEntry, error) {
 }
 
 func (c *Client) UpdateTimeEntry(timeEntry TimeEntry) error {
-	var ir timeEntryRequest
-	ir.TimeEntry = timeEntry
-	s, err := json.Marshal(ir)
-	if err != nil {
-		return err
-	}
-	req, err := http.NewRequest("PUT", c.endpoint+"/time_entries/"+strconv.Itoa(timeEntry.Id)+".json?key="+c.apikey, strings.NewReader(string(s)))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	res, err := c.Do(req)
-	if err != nil {
-		return err
-	}
-	defer res.Body.Close()
+	return c.UpdateTimeEntryContext(context.Background(), timeEntry)
+}
 
-	if res.StatusCode == 404 {
-		return errors.New("Not Found")
-	}
-	if res.StatusCode != 200 {
-		decoder := json.NewDecoder(res.Body)
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	}
-	if err != nil {
-		return err
-	}
-	return err
+func (c *Client) UpdateTimeEntryContext(ctx context.Context, timeEntry TimeEntry) error {
+	return c.put(ctx, "/time_entries/"+strconv.Itoa(timeEntry.Id)+".json", timeEntryRequest{TimeEntry: timeEntry}, nil)
 }
 
 func (c *Client) DeleteTimeEntry(id int) error {
-	req, err := http.NewRequest("DELETE", c.endpoint+"/time_entries/"+strconv.Itoa(id)+".json?key="+c.apikey, strings.NewReader(""))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	res, err := c.Do(req)
-	if err != nil {
-		return err
-	}
-	defer res.Body.Close()
-
-	if res.StatusCode == 404 {
-		return errors.New("Not Found")
-	}
+	return c.DeleteTimeEntryContext(context.Background(), id)
+}
 
-	decoder := json.NewDecoder(res.Body)
-	if res.StatusCode != 200 {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	}
-	return err
+func (c *Client) DeleteTimeEntryContext(ctx context.Context, id int) error {
+	return c.delete(ctx, "/time_entries/"+strconv.Itoa(id)+".json")
 }
@@ -1,9 +1,7 @@
 package redmine
 
 import (
-	"encoding/json"
-	"errors"
-	"strings"
+	"context"
 )
 
 type timeEntryActivitiesResult struct {
@@ -17,23 +15,12 @@ type TimeEntryActivity struct {
 }
 
 func (c *Client) TimeEntryActivities() ([]TimeEntryActivity, error) {
-	res, err := c.Get(c.endpoint + "/enumerations/time_entry_activities.json?key=" + c.apikey + c.getPaginationClause())
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
+	return c.TimeEntryActivitiesContext(context.Background())
+}
 
-	decoder := json.NewDecoder(res.Body)
+func (c *Client) TimeEntryActivitiesContext(ctx context.Context) ([]TimeEntryActivity, error) {
 	var r timeEntryActivitiesResult
-	if res.StatusCode != 200 {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	} else {
-		err = decoder.Decode(&r)
-	}
+	err := c.get(ctx, c.pathWithParameters("/enumerations/time_entry_activities.json", c.getPaginationClause()), &r)
 	if err != nil {
 		return nil, err
 	}
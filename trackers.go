@@ -1,10 +1,7 @@
 package redmine
 
 import (
-	"encoding/json"
-	"errors"
-	"net/http"
-	"strings"
+	"context"
 )
 
 type trackersResult struct {
@@ -12,23 +9,12 @@ type trackersResult struct {
 }
 
 func (c *Client) Trackers() ([]IdName, error) {
-	res, err := http.Get(c.endpoint + "/trackers.json?key=" + c.apikey + c.getPaginationClause())
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
+	return c.TrackersContext(context.Background())
+}
 
-	decoder := json.NewDecoder(res.Body)
+func (c *Client) TrackersContext(ctx context.Context) ([]IdName, error) {
 	var r trackersResult
-	if res.StatusCode != 200 {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	} else {
-		err = decoder.Decode(&r)
-	}
+	err := c.get(ctx, c.pathWithParameters("/trackers.json", c.getPaginationClause()), &r)
 	if err != nil {
 		return nil, err
 	}
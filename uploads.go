@@ -1,53 +1,136 @@
 package redmine
 
 import (
-	"bytes"
-	"encoding/json"
+	"context"
 	"errors"
-	"io/ioutil"
+	"io"
 	"net/http"
-	"strings"
+	"net/url"
+	"os"
+	"path/filepath"
 )
 
 type uploadResponse struct {
 	Upload Upload `json:"upload"`
 }
 
+// Upload references a file uploaded with Upload or UploadFrom. It is attached to an issue, a wiki page, a version or
+// a project by adding it to the uploads of the container.
 type Upload struct {
 	Token       string `json:"token"`
 	Filename    string `json:"filename"`
 	ContentType string `json:"content_type"`
+	Description string `json:"description,omitempty"`
 }
 
+// UploadOptions contains the optional parameters of UploadFrom.
+type UploadOptions struct {
+	// Filename is the name of the attachment. Redmine generates a random name if it is empty.
+	Filename string
+	// ContentType is the MIME type of the attachment. Redmine guesses it from the filename if it is empty.
+	ContentType string
+	// Size is the number of bytes which will be read from the reader. It is sent as Content-Length and must be exact.
+	// If it is 0 or less, the content is sent with chunked transfer encoding.
+	Size int64
+	// Progress is called after every chunk read from the reader with the number of bytes uploaded so far and Size
+	// (or -1 if the size is unknown).
+	Progress func(uploaded int64, total int64)
+}
+
+// progressReader reports the number of bytes read from the underlying reader.
+type progressReader struct {
+	io.Reader
+	size     int64
+	uploaded int64
+	progress func(uploaded int64, total int64)
+}
+
+func (r *progressReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if n > 0 {
+		r.uploaded += int64(n)
+		if r.progress != nil {
+			r.progress(r.uploaded, r.size)
+		}
+	}
+	return n, err
+}
+
+// Seek allows to send the content again when the upload request is retried. It fails if the underlying reader is not
+// an io.Seeker. Rewinding resets the progress.
+func (r *progressReader) Seek(offset int64, whence int) (int64, error) {
+	seeker, ok := r.Reader.(io.Seeker)
+	if !ok {
+		return 0, errors.New("upload content is not seekable")
+	}
+	position, err := seeker.Seek(offset, whence)
+	if err == nil && whence == io.SeekStart {
+		r.uploaded = 0
+	}
+	return position, err
+}
+
+// ContentLength returns the size announced in the Content-Length header of the upload request.
+func (r *progressReader) ContentLength() int64 {
+	return r.size
+}
+
+// Upload uploads the file with the given name. The file is read again if the request is retried, see
+// RetryPolicy.RetryPOST.
 func (c *Client) Upload(filename string) (*Upload, error) {
-	content, err := ioutil.ReadFile(filename)
+	return c.UploadContext(context.Background(), filename)
+}
+
+// UploadContext is like Upload but binds the request to ctx.
+func (c *Client) UploadContext(ctx context.Context, filename string) (*Upload, error) {
+	file, err := os.Open(filename)
 	if err != nil {
 		return nil, err
 	}
-	req, err := http.NewRequest("POST", c.endpoint+"/uploads.json?key="+c.apikey, bytes.NewBuffer(content))
+	defer file.Close()
+	info, err := file.Stat()
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Set("Content-Type", "application/octet-stream")
-	res, err := c.Do(req)
-	if err != nil {
-		return nil, err
+
+	return c.UploadFromContext(ctx, file, UploadOptions{Filename: filepath.Base(filename), Size: info.Size()})
+}
+
+// UploadFrom streams the content of r to Redmine without buffering it in memory. The returned Upload contains the
+// token and the filename and content type of options so that it can be attached right away. The upload is only
+// retried according to RetryPolicy.RetryPOST if r is an io.Seeker like *os.File; plain readers cannot be sent again.
+func (c *Client) UploadFrom(r io.Reader, options UploadOptions) (*Upload, error) {
+	return c.UploadFromContext(context.Background(), r, options)
+}
+
+// UploadFromContext is like UploadFrom but binds the request to ctx.
+func (c *Client) UploadFromContext(ctx context.Context, r io.Reader, options UploadOptions) (*Upload, error) {
+	size := options.Size
+	if size <= 0 {
+		size = -1
 	}
-	defer res.Body.Close()
+	body := &progressReader{Reader: r, size: size, progress: options.Progress}
 
-	decoder := json.NewDecoder(res.Body)
-	var r uploadResponse
-	if res.StatusCode != 201 {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	} else {
-		err = decoder.Decode(&r)
+	parameters := []string{}
+	if options.Filename != "" {
+		parameters = append(parameters, "filename="+url.QueryEscape(options.Filename))
 	}
+	if options.ContentType != "" {
+		parameters = append(parameters, "content_type="+url.QueryEscape(options.ContentType))
+	}
+
+	var res uploadResponse
+	err := c.doRaw(ctx, http.MethodPost, c.pathWithParameters("/uploads.json", parameters...), body, "application/octet-stream", &res)
 	if err != nil {
 		return nil, err
 	}
-	return &r.Upload, nil
+
+	upload := res.Upload
+	if upload.Filename == "" {
+		upload.Filename = options.Filename
+	}
+	if upload.ContentType == "" {
+		upload.ContentType = options.ContentType
+	}
+	return &upload, nil
 }
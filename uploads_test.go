@@ -0,0 +1,174 @@
+package redmine
+
+import (
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestClient_UploadFrom(t *testing.T) {
+	t.Run("should stream the content with filename and size", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "/uploads.json", r.URL.Path)
+			assert.Equal(t, "build log.txt", r.URL.Query().Get("filename"))
+			assert.Equal(t, "text/plain", r.URL.Query().Get("content_type"))
+			assert.Equal(t, "application/octet-stream", r.Header.Get("Content-Type"))
+			assert.Equal(t, int64(11), r.ContentLength)
+			body, _ := ioutil.ReadAll(r.Body)
+			assert.Equal(t, "hello world", string(body))
+			w.WriteHeader(http.StatusCreated)
+			_, _ = fmt.Fprintln(w, `{"upload": {"id": 7, "token": "7.ed32257a2ab0f7526c0d72c32994c58b"}}`)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+		var progress []int64
+
+		actual, err := sut.UploadFrom(strings.NewReader("hello world"), UploadOptions{
+			Filename:    "build log.txt",
+			ContentType: "text/plain",
+			Size:        11,
+			Progress: func(uploaded int64, total int64) {
+				assert.Equal(t, int64(11), total)
+				progress = append(progress, uploaded)
+			},
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, &Upload{Token: "7.ed32257a2ab0f7526c0d72c32994c58b", Filename: "build log.txt", ContentType: "text/plain"}, actual)
+		require.NotEmpty(t, progress)
+		assert.Equal(t, int64(11), progress[len(progress)-1])
+	})
+
+	t.Run("should send content of unknown size chunked", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "", r.URL.RawQuery)
+			assert.Equal(t, []string{"chunked"}, r.TransferEncoding)
+			body, _ := ioutil.ReadAll(r.Body)
+			assert.Equal(t, "piped", string(body))
+			w.WriteHeader(http.StatusCreated)
+			_, _ = fmt.Fprintln(w, `{"upload": {"token": "token"}}`)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+		pipeReader, pipeWriter := io.Pipe()
+		go func() {
+			_, _ = pipeWriter.Write([]byte("piped"))
+			_ = pipeWriter.Close()
+		}()
+		var total int64
+
+		actual, err := sut.UploadFrom(pipeReader, UploadOptions{Progress: func(_ int64, t int64) { total = t }})
+
+		require.NoError(t, err)
+		assert.Equal(t, "token", actual.Token)
+		assert.Equal(t, int64(-1), total)
+	})
+}
+
+func TestClient_Upload(t *testing.T) {
+	t.Run("should upload the file with its name", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "go-redmine")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "notes.txt")
+		require.NoError(t, ioutil.WriteFile(path, []byte("notes"), 0600))
+
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "notes.txt", r.URL.Query().Get("filename"))
+			assert.Equal(t, int64(5), r.ContentLength)
+			w.WriteHeader(http.StatusCreated)
+			_, _ = fmt.Fprintln(w, `{"upload": {"token": "token"}}`)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		actual, err := sut.Upload(path)
+
+		require.NoError(t, err)
+		assert.Equal(t, &Upload{Token: "token", Filename: "notes.txt"}, actual)
+	})
+
+	t.Run("should send the file again when retrying", func(t *testing.T) {
+		dir, err := ioutil.TempDir("", "go-redmine")
+		require.NoError(t, err)
+		defer os.RemoveAll(dir)
+		path := filepath.Join(dir, "notes.txt")
+		require.NoError(t, ioutil.WriteFile(path, []byte("notes"), 0600))
+
+		var bodies []string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			body, _ := ioutil.ReadAll(r.Body)
+			bodies = append(bodies, string(body))
+			if len(bodies) == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			_, _ = fmt.Fprintln(w, `{"upload": {"token": "token"}}`)
+		}))
+		defer ts.Close()
+		policy := RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, RetryPOST: true}
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"), WithRetryPolicy(policy))
+
+		actual, err := sut.Upload(path)
+
+		require.NoError(t, err)
+		assert.Equal(t, "token", actual.Token)
+		assert.Equal(t, []string{"notes", "notes"}, bodies)
+	})
+}
+
+func TestClient_UploadFrom_retries(t *testing.T) {
+	newServer := func(attempts *int) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = ioutil.ReadAll(r.Body)
+			*attempts++
+			if *attempts == 1 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusCreated)
+			_, _ = fmt.Fprintln(w, `{"upload": {"token": "token"}}`)
+		}))
+	}
+	policy := RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, RetryPOST: true}
+
+	t.Run("should rewind seekable readers and reset the progress", func(t *testing.T) {
+		attempts := 0
+		ts := newServer(&attempts)
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"), WithRetryPolicy(policy))
+
+		var uploaded int64
+		_, err := sut.UploadFrom(strings.NewReader("hello"), UploadOptions{Size: 5, Progress: func(n int64, total int64) {
+			uploaded = n
+		}})
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, attempts)
+		assert.Equal(t, int64(5), uploaded)
+	})
+
+	t.Run("should not retry plain readers", func(t *testing.T) {
+		attempts := 0
+		ts := newServer(&attempts)
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"), WithRetryPolicy(policy))
+
+		_, err := sut.UploadFrom(io.MultiReader(strings.NewReader("hello")), UploadOptions{Size: 5})
+
+		assert.True(t, errors.Is(err, ErrServer))
+		assert.Equal(t, 1, attempts)
+	})
+}
@@ -1,13 +1,8 @@
 package redmine
 
 import (
-	"bytes"
-	"encoding/json"
-	"errors"
-	"fmt"
-	"net/http"
+	"context"
 	"strconv"
-	"strings"
 )
 
 type userResult struct {
@@ -15,23 +10,50 @@ type userResult struct {
 }
 
 type usersResult struct {
-	Users []User `json:"users"`
+	Users      []User `json:"users"`
+	TotalCount int    `json:"total_count"`
 }
 
-type User struct {
-	Id           int            `json:"id"`
-	Login        string         `json:"login"`
-	Firstname    string         `json:"firstname"`
-	Lastname     string         `json:"lastname"`
-	Mail         string         `json:"mail"`
-	CreatedOn    string         `json:"created_on"`
-	LatLoginOn   string         `json:"last_login_on"`
-	Memberships  []Membership   `json:"memberships"`
-	CustomFields []*CustomField `json:"custom_fields,omitempty"`
+type userRequest struct {
+	User            User `json:"user"`
+	SendInformation bool `json:"send_information,omitempty"`
 }
 
-type totalcount struct {
-	TotalCount int `json:"total_count"`
+type User struct {
+	Id    int    `json:"id"`
+	Login string `json:"login,omitempty"`
+	// Admin is only returned to administrators. It is only sent by CreateUser and UpdateUser if it is not nil, so that
+	// updating other fields keeps the admin rights of the user.
+	Admin           *bool          `json:"admin,omitempty"`
+	Firstname       string         `json:"firstname,omitempty"`
+	Lastname        string         `json:"lastname,omitempty"`
+	Mail            string         `json:"mail,omitempty"`
+	Status          int            `json:"status,omitempty"`
+	APIKey          string         `json:"api_key,omitempty"`
+	CreatedOn       DateTime       `json:"created_on"`
+	LatLoginOn      DateTime       `json:"last_login_on"`
+	PasswdChangedOn DateTime       `json:"passwd_changed_on"`
+	Memberships     []Membership   `json:"memberships"`
+	Groups          []IdName       `json:"groups,omitempty"`
+	CustomFields    []*CustomField `json:"custom_fields,omitempty"`
+
+	// The following fields are only sent when creating or updating a user and are never returned by Redmine.
+
+	// Password sets the password of the user. It is ignored if the user authenticates against an authentication
+	// source.
+	Password string `json:"password,omitempty"`
+	// GeneratePassword lets Redmine generate a random password instead of setting Password.
+	GeneratePassword bool `json:"generate_password,omitempty"`
+	// AuthSourceId authenticates the user against the authentication source (f. e. LDAP) with this id.
+	AuthSourceId int `json:"auth_source_id,omitempty"`
+	// MailNotification sets the email notification option of the user, f. e. "all", "selected", "only_my_events",
+	// "only_assigned", "only_owner" or "none".
+	MailNotification string `json:"mail_notification,omitempty"`
+	// MustChangePasswd forces the user to change the password on the next login.
+	MustChangePasswd bool `json:"must_change_passwd,omitempty"`
+	// SendInformation sends the account information including the password to the user. It is only used by
+	// CreateUser and UpdateUser.
+	SendInformation bool `json:"-"`
 }
 
 type Status struct {
@@ -85,131 +107,83 @@ func (uif *UserByIdFilter) Include(include string) {
 }
 
 func (c *Client) Users() ([]User, error) {
-	res, err := c.Get(c.endpoint + "/users.json?key=" + c.apikey + c.getPaginationClause())
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
+	return c.UsersContext(context.Background())
+}
 
-	decoder := json.NewDecoder(res.Body)
+func (c *Client) UsersContext(ctx context.Context) ([]User, error) {
 	var r usersResult
-	if res.StatusCode != 200 {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	} else {
-		err = decoder.Decode(&r)
-	}
+	err := c.get(ctx, c.pathWithParameters("/users.json", c.getPaginationClause()), &r)
 	if err != nil {
 		return nil, err
 	}
 	return r.Users, nil
 }
 
-func (c *Client) totalCount() (int, error) {
-	res, err := c.Get(c.endpoint + "/users.json?key=" + c.apikey + c.getPaginationClause())
-	if err != nil {
-		return 0, err
-	}
-	defer res.Body.Close()
-	decoder := json.NewDecoder(res.Body)
-	var r totalcount
-	if res.StatusCode != 200 {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	} else {
-		err = decoder.Decode(&r)
+// AllUsers fetches all users page by page.
+func (c *Client) AllUsers() ([]User, error) {
+	return c.AllUsersContext(context.Background())
+}
 
+// AllUsersContext is like AllUsers but binds the requests to ctx.
+func (c *Client) AllUsersContext(ctx context.Context) ([]User, error) {
+	var allUsers []User
+	it := c.IterateUsers(ctx, nil, 100)
+	for it.Next() {
+		allUsers = append(allUsers, it.Item())
 	}
-	if err != nil {
-		return 0, err
+	if err := it.Err(); err != nil {
+		return nil, err
 	}
-	return r.TotalCount, nil
+	return allUsers, nil
 }
 
-func (c *Client) AllUsers() ([]User, error) {
-	count, err := c.totalCount()
-	if err != nil {
-		return nil, err
+// UserIterator iterates over the users of a list request, see Paginator.
+type UserIterator struct {
+	*Paginator
+	page []User
+}
+
+// Item returns the current user.
+func (it *UserIterator) Item() User {
+	return it.page[it.index]
+}
+
+// IterateUsers returns an iterator over all users matching filter, which may be nil. Every request fetches up to
+// pageSize users; values below 1 use Redmine's default page size.
+func (c *Client) IterateUsers(ctx context.Context, filter *UsersFilter, pageSize int) *UserIterator {
+	parameters := ""
+	if filter != nil {
+		parameters = filter.ToURLParams()
 	}
-	var allUsers []User
-	c.Limit = 100
-	for i := 0; i < count; i = i + 100 {
-		c.Offset = i
-		users, err := c.Users()
+
+	it := &UserIterator{}
+	it.Paginator = newPaginator(ctx, pageSize, func(ctx context.Context, pagination ...string) (int, int, error) {
+		var r usersResult
+		err := c.get(ctx, c.pathWithParameters("/users.json", append(pagination, parameters)...), &r)
 		if err != nil {
-			return nil, err
+			return 0, 0, err
 		}
-		allUsers = append(allUsers, users...)
-	}
-	c.Offset = 0
-	return allUsers, nil
+		it.page = r.Users
+		return len(r.Users), r.TotalCount, nil
+	})
+	return it
 }
 
 func (c *Client) SetUserStatus(status Status, userID int) error {
-	s, err := json.Marshal(status)
-	if err != nil {
-		return err
-	}
-	url := fmt.Sprintf(c.endpoint+"/users/%d.json?key="+c.apikey, userID)
-	req, err := http.NewRequest("PUT", url, bytes.NewBuffer(s))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	res, err := c.Do(req)
-	if err != nil {
-		return err
-	}
-	defer res.Body.Close()
-	if res.StatusCode != 204 {
-		decoder := json.NewDecoder(res.Body)
-		if !isHTTPStatusSuccessful(res.StatusCode, []int{http.StatusCreated}) {
-			var er errorsResult
-			err = decoder.Decode(&er)
-			if err == nil {
-				err = errors.New(strings.Join(er.Errors, "\n"))
-			}
-		}
-		if err != nil {
-			return err
-		}
-	}
-	return nil
+	return c.SetUserStatusContext(context.Background(), status, userID)
+}
+
+func (c *Client) SetUserStatusContext(ctx context.Context, status Status, userID int) error {
+	return c.put(ctx, "/users/"+strconv.Itoa(userID)+".json", status, nil)
 }
 
 func (c *Client) UsersWithFilter(filter *UsersFilter) ([]User, error) {
-	uri, err := c.URLWithFilter("/users.json", filter.Filter)
-	if err != nil {
-		return nil, err
-	}
-	req, err := http.NewRequest("GET", uri, nil)
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Add("X-Redmine-API-Key", c.apikey)
-	res, err := c.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
+	return c.UsersWithFilterContext(context.Background(), filter)
+}
 
-	decoder := json.NewDecoder(res.Body)
+func (c *Client) UsersWithFilterContext(ctx context.Context, filter *UsersFilter) ([]User, error) {
 	var r usersResult
-	if res.StatusCode != 200 {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	} else {
-		err = decoder.Decode(&r)
-	}
+	err := c.get(ctx, c.pathWithParameters("/users.json", c.getPaginationClause(), filter.ToURLParams()), &r)
 	if err != nil {
 		return nil, err
 	}
@@ -217,23 +191,12 @@ func (c *Client) UsersWithFilter(filter *UsersFilter) ([]User, error) {
 }
 
 func (c *Client) User(id int) (*User, error) {
-	res, err := c.Get(c.endpoint + "/users/" + strconv.Itoa(id) + ".json?key=" + c.apikey)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
+	return c.UserContext(context.Background(), id)
+}
 
-	decoder := json.NewDecoder(res.Body)
+func (c *Client) UserContext(ctx context.Context, id int) (*User, error) {
 	var r userResult
-	if res.StatusCode != 200 {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	} else {
-		err = decoder.Decode(&r)
-	}
+	err := c.get(ctx, "/users/"+strconv.Itoa(id)+".json", &r)
 	if err != nil {
 		return nil, err
 	}
@@ -241,35 +204,66 @@ func (c *Client) User(id int) (*User, error) {
 }
 
 func (c *Client) UserByIdAndFilter(id int, filter *UserByIdFilter) (*User, error) {
-	uri, err := c.URLWithFilter("/users/"+strconv.Itoa(id)+".json", filter.Filter)
-	if err != nil {
-		return nil, err
-	}
+	return c.UserByIdAndFilterContext(context.Background(), id, filter)
+}
 
-	req, err := http.NewRequest("GET", uri, nil)
+func (c *Client) UserByIdAndFilterContext(ctx context.Context, id int, filter *UserByIdFilter) (*User, error) {
+	var r userResult
+	err := c.get(ctx, c.pathWithParameters("/users/"+strconv.Itoa(id)+".json", filter.ToURLParams()), &r)
 	if err != nil {
 		return nil, err
 	}
-	req.Header.Add("X-Redmine-API-Key", c.apikey)
-	res, err := c.Do(req)
+	return &r.User, nil
+}
+
+// CurrentUser returns the user whose credentials are used by the client, including the API key.
+func (c *Client) CurrentUser() (*User, error) {
+	return c.CurrentUserContext(context.Background())
+}
+
+// CurrentUserContext is like CurrentUser but binds the request to ctx.
+func (c *Client) CurrentUserContext(ctx context.Context) (*User, error) {
+	var r userResult
+	err := c.get(ctx, "/users/current.json", &r)
 	if err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
+	return &r.User, nil
+}
+
+// CreateUser creates a user. Login, Firstname, Lastname and Mail are mandatory as well as Password unless
+// GeneratePassword or AuthSourceId is set. This requires admin privileges.
+func (c *Client) CreateUser(user User) (*User, error) {
+	return c.CreateUserContext(context.Background(), user)
+}
 
-	decoder := json.NewDecoder(res.Body)
+// CreateUserContext is like CreateUser but binds the request to ctx.
+func (c *Client) CreateUserContext(ctx context.Context, user User) (*User, error) {
 	var r userResult
-	if res.StatusCode != 200 {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	} else {
-		err = decoder.Decode(&r)
-	}
+	err := c.post(ctx, "/users.json", userRequest{User: user, SendInformation: user.SendInformation}, &r)
 	if err != nil {
 		return nil, err
 	}
 	return &r.User, nil
 }
+
+// UpdateUser updates the user with the id user.Id. Empty fields like Mail and a nil Admin keep their current values.
+// This requires admin privileges.
+func (c *Client) UpdateUser(user User) error {
+	return c.UpdateUserContext(context.Background(), user)
+}
+
+// UpdateUserContext is like UpdateUser but binds the request to ctx.
+func (c *Client) UpdateUserContext(ctx context.Context, user User) error {
+	return c.put(ctx, "/users/"+strconv.Itoa(user.Id)+".json", userRequest{User: user, SendInformation: user.SendInformation}, nil)
+}
+
+// DeleteUser deletes the user with the given id. This requires admin privileges.
+func (c *Client) DeleteUser(id int) error {
+	return c.DeleteUserContext(context.Background(), id)
+}
+
+// DeleteUserContext is like DeleteUser but binds the request to ctx.
+func (c *Client) DeleteUserContext(ctx context.Context, id int) error {
+	return c.delete(ctx, "/users/"+strconv.Itoa(id)+".json")
+}
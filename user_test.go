@@ -1,39 +1,226 @@
 package redmine
 
 import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 )
 
-const REDMINE_TEST_ENDPOINT = "https://placeholder.com/redmine"
-
 func TestClient_SetUserStatus(t *testing.T) {
-	c := NewClient(REDMINE_TEST_ENDPOINT, "")
-	statue := Status{}
-	statue.User.Status = 3
-	err := c.SetUserStatus(statue, 304)
-	if err != nil {
-		t.Fatalf(err.Error())
-	}
+	t.Run("should send the new status", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPut, r.Method)
+			assert.Equal(t, "/users/304.json", r.URL.Path)
+			body, _ := ioutil.ReadAll(r.Body)
+			assert.JSONEq(t, `{"user": {"status": 3}}`, string(body))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer ts.Close()
+		c := NewClient(ts.URL)
+		status := Status{}
+		status.User.Status = 3
+
+		err := c.SetUserStatus(status, 304)
+
+		require.NoError(t, err)
+	})
 }
 
 func TestClient_GetAllUser(t *testing.T) {
-	c := NewClient(REDMINE_TEST_ENDPOINT, "")
-	statue := Status{}
-	statue.User.Status = 3
-	Users, err := c.AllUsers()
-	if err != nil {
-		t.Fatalf(err.Error())
-	}
-	print(len(Users))
+	t.Run("should fetch all pages without changing the pagination settings of the client", func(t *testing.T) {
+		var offsets []string
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/users.json", r.URL.Path)
+			assert.Equal(t, "100", r.URL.Query().Get("limit"))
+			offsets = append(offsets, r.URL.Query().Get("offset"))
+			if r.URL.Query().Get("offset") == "0" {
+				_, _ = fmt.Fprintln(w, `{"users": [{"id": 1, "login": "admin"}, {"id": 2, "login": "jsmith"}], "total_count": 3}`)
+				return
+			}
+			_, _ = fmt.Fprintln(w, `{"users": [{"id": 3, "login": "dlopper"}], "total_count": 3}`)
+		}))
+		defer ts.Close()
+		c := NewClient(ts.URL, WithPageSize(25))
+		c.Offset = 50
+
+		users, err := c.AllUsers()
+
+		require.NoError(t, err)
+		require.Len(t, users, 3)
+		assert.Equal(t, "dlopper", users[2].Login)
+		assert.Equal(t, []string{"0", "2"}, offsets)
+		assert.Equal(t, 25, c.Limit)
+		assert.Equal(t, 50, c.Offset)
+	})
 }
 
-func TestClient_GetTotalCount(t *testing.T) {
-	c := NewClient(REDMINE_TEST_ENDPOINT, "")
-	statue := Status{}
-	statue.User.Status = 3
-	num, err := c.totalCount()
-	if err != nil {
-		t.Fatalf(err.Error())
-	}
-	print(num)
+func TestClient_CurrentUser(t *testing.T) {
+	t.Run("should parse the current user including API key and groups", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/users/current.json", r.URL.Path)
+			_, _ = fmt.Fprintln(w, `{
+  "user": {
+    "id": 1,
+    "login": "admin",
+    "admin": true,
+    "firstname": "Redmine",
+    "lastname": "Admin",
+    "mail": "admin@example.net",
+    "created_on": "2021-02-19T16:49:52Z",
+    "last_login_on": "2021-03-01T09:12:40Z",
+    "passwd_changed_on": "2021-02-20T08:00:00Z",
+    "api_key": "ebc3f6b781a6fb3f2b0a83ce0ebb80e0d585189d",
+    "status": 1,
+    "groups": [{"id": 5, "name": "Developers"}]
+  }
+}`)
+		}))
+		defer ts.Close()
+		c := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		actual, err := c.CurrentUser()
+
+		require.NoError(t, err)
+		assert.Equal(t, "admin", actual.Login)
+		require.NotNil(t, actual.Admin)
+		assert.True(t, *actual.Admin)
+		assert.Equal(t, 1, actual.Status)
+		assert.Equal(t, "ebc3f6b781a6fb3f2b0a83ce0ebb80e0d585189d", actual.APIKey)
+		assert.Equal(t, []IdName{{Id: 5, Name: "Developers"}}, actual.Groups)
+		assert.Equal(t, "2021-02-20T08:00:00Z", actual.PasswdChangedOn.String())
+	})
+}
+
+func TestClient_CreateUser(t *testing.T) {
+	t.Run("should send the write-only fields and return the created user", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "/users.json", r.URL.Path)
+			var body map[string]interface{}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			assert.Equal(t, true, body["send_information"])
+			user := body["user"].(map[string]interface{})
+			assert.Equal(t, "jsmith", user["login"])
+			assert.Equal(t, true, user["generate_password"])
+			assert.Equal(t, true, user["must_change_passwd"])
+			assert.Equal(t, "only_my_events", user["mail_notification"])
+			assert.NotContains(t, user, "password")
+			assert.NotContains(t, user, "SendInformation")
+
+			w.WriteHeader(http.StatusCreated)
+			_, _ = fmt.Fprintln(w, `{"user": {"id": 7, "login": "jsmith", "status": 1}}`)
+		}))
+		defer ts.Close()
+		c := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		actual, err := c.CreateUser(User{
+			Login:            "jsmith",
+			Firstname:        "John",
+			Lastname:         "Smith",
+			Mail:             "jsmith@example.net",
+			GeneratePassword: true,
+			MustChangePasswd: true,
+			MailNotification: "only_my_events",
+			SendInformation:  true,
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 7, actual.Id)
+	})
+
+	t.Run("should return validation errors", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusUnprocessableEntity)
+			_, _ = fmt.Fprintln(w, `{"errors": ["Login has already been taken"]}`)
+		}))
+		defer ts.Close()
+		c := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		actual, err := c.CreateUser(User{Login: "admin"})
+
+		assert.Nil(t, actual)
+		assert.True(t, errors.Is(err, ErrValidation))
+		assert.Contains(t, err.Error(), "Login has already been taken")
+	})
+}
+
+func TestClient_UpdateUser(t *testing.T) {
+	t.Run("should put the user", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPut, r.Method)
+			assert.Equal(t, "/users/7.json", r.URL.Path)
+			var body userRequest
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			require.NotNil(t, body.User.Admin)
+			assert.True(t, *body.User.Admin)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer ts.Close()
+		c := NewClient(ts.URL, WithAPIKey("apiKey"))
+		admin := true
+
+		err := c.UpdateUser(User{Id: 7, Login: "jsmith", Admin: &admin})
+
+		require.NoError(t, err)
+	})
+
+	t.Run("should keep admin rights and omitted fields on partial updates", func(t *testing.T) {
+		var body struct {
+			User map[string]interface{} `json:"user"`
+		}
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer ts.Close()
+		c := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		err := c.UpdateUser(User{Id: 3, Mail: "jsmith@example.net"})
+
+		require.NoError(t, err)
+		assert.Equal(t, "jsmith@example.net", body.User["mail"])
+		for _, key := range []string{"admin", "login", "firstname", "lastname", "password", "status"} {
+			assert.NotContains(t, body.User, key)
+		}
+	})
+
+	t.Run("should revoke admin rights if Admin is false", func(t *testing.T) {
+		var body struct {
+			User map[string]interface{} `json:"user"`
+		}
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer ts.Close()
+		c := NewClient(ts.URL, WithAPIKey("apiKey"))
+		admin := false
+
+		err := c.UpdateUser(User{Id: 3, Admin: &admin})
+
+		require.NoError(t, err)
+		assert.Equal(t, false, body.User["admin"])
+	})
+}
+
+func TestClient_DeleteUser(t *testing.T) {
+	t.Run("should delete the user", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodDelete, r.Method)
+			assert.Equal(t, "/users/7.json", r.URL.Path)
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer ts.Close()
+		c := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		err := c.DeleteUser(7)
+
+		require.NoError(t, err)
+	})
 }
@@ -1,11 +1,8 @@
 package redmine
 
 import (
-	"encoding/json"
-	"errors"
-	"net/http"
+	"context"
 	"strconv"
-	"strings"
 )
 
 type versionRequest struct {
@@ -17,7 +14,8 @@ type versionResult struct {
 }
 
 type versionsResult struct {
-	Versions []Version `json:"versions"`
+	Versions   []Version `json:"versions"`
+	TotalCount int       `json:"total_count"`
 }
 
 type Version struct {
@@ -26,34 +24,19 @@ type Version struct {
 	Name         string         `json:"name"`
 	Description  string         `json:"description"`
 	Status       string         `json:"status"`
-	DueDate      string         `json:"due_date"`
-	CreatedOn    string         `json:"created_on"`
-	UpdatedOn    string         `json:"updated_on"`
+	DueDate      Date           `json:"due_date"`
+	CreatedOn    DateTime       `json:"created_on"`
+	UpdatedOn    DateTime       `json:"updated_on"`
 	CustomFields []*CustomField `json:"custom_fields,omitempty"`
 }
 
 func (c *Client) Version(id int) (*Version, error) {
-	res, err := c.Get(c.endpoint + "/versions/" + strconv.Itoa(id) + ".json?key=" + c.apikey)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	if res.StatusCode == 404 {
-		return nil, errors.New("Not Found")
-	}
+	return c.VersionContext(context.Background(), id)
+}
 
-	decoder := json.NewDecoder(res.Body)
+func (c *Client) VersionContext(ctx context.Context, id int) (*Version, error) {
 	var r versionResult
-	if res.StatusCode != 200 {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	} else {
-		err = decoder.Decode(&r)
-	}
+	err := c.get(ctx, "/versions/"+strconv.Itoa(id)+".json", &r)
 	if err != nil {
 		return nil, err
 	}
@@ -61,124 +44,70 @@ func (c *Client) Version(id int) (*Version, error) {
 }
 
 func (c *Client) Versions(projectId int) ([]Version, error) {
-	res, err := c.Get(c.endpoint + "/projects/" + strconv.Itoa(projectId) + "/versions.json?key=" + c.apikey + c.getPaginationClause())
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
-
-	if res.StatusCode == 404 {
-		return nil, errors.New("Not Found")
-	}
+	return c.VersionsContext(context.Background(), projectId)
+}
 
-	decoder := json.NewDecoder(res.Body)
+func (c *Client) VersionsContext(ctx context.Context, projectId int) ([]Version, error) {
 	var r versionsResult
-	if res.StatusCode != 200 {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	} else {
-		err = decoder.Decode(&r)
-	}
+	err := c.get(ctx, c.pathWithParameters("/projects/"+strconv.Itoa(projectId)+"/versions.json", c.getPaginationClause()), &r)
 	if err != nil {
 		return nil, err
 	}
 	return r.Versions, nil
 }
 
-func (c *Client) CreateVersion(version Version) (*Version, error) {
-	var ir versionRequest
-	ir.Version = version
-	s, err := json.Marshal(ir)
-	if err != nil {
-		return nil, err
-	}
-	req, err := http.NewRequest("POST", c.endpoint+"/projects/"+strconv.Itoa(version.Project.Id)+"/versions.json?key="+c.apikey, strings.NewReader(string(s)))
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	res, err := c.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
+// VersionIterator iterates over the versions of a list request, see Paginator.
+type VersionIterator struct {
+	*Paginator
+	page []Version
+}
 
-	if res.StatusCode == 404 {
-		return nil, errors.New("Not Found")
-	}
+// Item returns the current version.
+func (it *VersionIterator) Item() Version {
+	return it.page[it.index]
+}
 
-	decoder := json.NewDecoder(res.Body)
-	var r versionRequest
-	if res.StatusCode != 201 {
-		var er errorsResult
-		err = decoder.Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
+// IterateVersions returns an iterator over all versions of a project. Every request fetches up to pageSize versions;
+// values below 1 use Redmine's default page size.
+func (c *Client) IterateVersions(ctx context.Context, projectId int, pageSize int) *VersionIterator {
+	it := &VersionIterator{}
+	it.Paginator = newPaginator(ctx, pageSize, func(ctx context.Context, pagination ...string) (int, int, error) {
+		var r versionsResult
+		err := c.get(ctx, c.pathWithParameters("/projects/"+strconv.Itoa(projectId)+"/versions.json", pagination...), &r)
+		if err != nil {
+			return 0, 0, err
 		}
-	} else {
-		err = decoder.Decode(&r)
-	}
+		it.page = r.Versions
+		return len(r.Versions), r.TotalCount, nil
+	})
+	return it
+}
+
+func (c *Client) CreateVersion(version Version) (*Version, error) {
+	return c.CreateVersionContext(context.Background(), version)
+}
+
+func (c *Client) CreateVersionContext(ctx context.Context, version Version) (*Version, error) {
+	var r versionResult
+	err := c.post(ctx, "/projects/"+strconv.Itoa(version.Project.Id)+"/versions.json", versionRequest{Version: version}, &r)
 	if err != nil {
 		return nil, err
 	}
-	return &r.Version, err
+	return &r.Version, nil
 }
 
 func (c *Client) UpdateVersion(version Version) error {
-	var ir versionRequest
-	ir.Version = version
-	s, err := json.Marshal(ir)
-	if err != nil {
-		return err
-	}
-	req, err := http.NewRequest("PUT", c.endpoint+"/versions/"+strconv.Itoa(version.Id)+".json?key="+c.apikey, strings.NewReader(string(s)))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	res, err := c.Do(req)
-	if err != nil {
-		return err
-	}
-	defer res.Body.Close()
+	return c.UpdateVersionContext(context.Background(), version)
+}
 
-	if res.StatusCode == 404 {
-		return errors.New("Not Found")
-	}
-	if res.StatusCode != 200 {
-		var er errorsResult
-		err = json.NewDecoder(res.Body).Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	}
-	return err
+func (c *Client) UpdateVersionContext(ctx context.Context, version Version) error {
+	return c.put(ctx, "/versions/"+strconv.Itoa(version.Id)+".json", versionRequest{Version: version}, nil)
 }
 
 func (c *Client) DeleteVersion(id int) error {
-	req, err := http.NewRequest("DELETE", c.endpoint+"/versions/"+strconv.Itoa(id)+".json?key="+c.apikey, strings.NewReader(""))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	res, err := c.Do(req)
-	if err != nil {
-		return err
-	}
-	defer res.Body.Close()
+	return c.DeleteVersionContext(context.Background(), id)
+}
 
-	if res.StatusCode == 404 {
-		return errors.New("Not Found")
-	}
-	if res.StatusCode != 200 {
-		var er errorsResult
-		err = json.NewDecoder(res.Body).Decode(&er)
-		if err == nil {
-			err = errors.New(strings.Join(er.Errors, "\n"))
-		}
-	}
-	return err
+func (c *Client) DeleteVersionContext(ctx context.Context, id int) error {
+	return c.delete(ctx, "/versions/"+strconv.Itoa(id)+".json")
 }
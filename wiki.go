@@ -1,15 +1,13 @@
 package redmine
 
 import (
-	"encoding/json"
-	"errors"
-	"net/http"
+	"context"
 	"strconv"
-	"strings"
 )
 
 type wikiPagesResult struct {
-	WikiPages []WikiPage `json:"wiki_pages"`
+	WikiPages  []WikiPage `json:"wiki_pages"`
+	TotalCount int        `json:"total_count"`
 }
 
 type wikiPageResult struct {
@@ -27,11 +25,19 @@ type WikiPage struct {
 	Version   interface{} `json:"version,omitempty"`
 	Author    *IdName     `json:"author,omitempty"`
 	Comments  string      `json:"comments"`
-	CreatedOn string      `json:"created_on,omitempty"`
-	UpdatedOn string      `json:"updated_on,omitempty"`
+	CreatedOn DateTime    `json:"created_on,omitempty"`
+	UpdatedOn DateTime    `json:"updated_on,omitempty"`
 	ParentID  int         `json:"parent_id"`
+	// Attachments is only filled if requested with WikiPageIncludeAttachments.
+	Attachments []Attachment `json:"attachments,omitempty"`
+	// Uploads attaches uploaded files when creating or updating the wiki page.
+	Uploads []*Upload `json:"uploads,omitempty"`
 }
 
+const (
+	WikiPageIncludeAttachments string = "attachments"
+)
+
 type Parent struct {
 	Title string `json:"title"`
 }
@@ -39,159 +45,107 @@ type Parent struct {
 // WikiPages fetches a list of all wiki pages of the given project.
 // The Text field of the listed pages is not fetch by this command and is thus empty.
 func (c *Client) WikiPages(projectId int) ([]WikiPage, error) {
-	res, err := c.Get(c.endpoint + "/projects/" + strconv.Itoa(projectId) + "/wiki/index.json?key=" + c.apikey + c.getPaginationClause())
+	return c.WikiPagesContext(context.Background(), projectId)
+}
+
+// WikiPagesContext is like WikiPages but binds the request to ctx.
+func (c *Client) WikiPagesContext(ctx context.Context, projectId int) ([]WikiPage, error) {
+	var r wikiPagesResult
+	err := c.get(ctx, c.pathWithParameters("/projects/"+strconv.Itoa(projectId)+"/wiki/index.json", c.getPaginationClause()), &r)
 	if err != nil {
 		return nil, err
 	}
-	defer res.Body.Close()
+	return r.WikiPages, nil
+}
 
-	decoder := json.NewDecoder(res.Body)
-	var r wikiPagesResult
-	if res.StatusCode == 404 {
-		return nil, errors.New("Not Found")
-	}
-	if res.StatusCode != 200 {
-		var er errorsResult
-		if err = decoder.Decode(&er); err != nil {
-			return nil, err
-		}
-		return nil, errors.New(strings.Join(er.Errors, "\n"))
-	} else {
-		if err = decoder.Decode(&r); err != nil {
-			return nil, err
+// WikiPageIterator iterates over the wiki pages of a list request, see Paginator.
+type WikiPageIterator struct {
+	*Paginator
+	page []WikiPage
+}
+
+// Item returns the current wiki page.
+func (it *WikiPageIterator) Item() WikiPage {
+	return it.page[it.index]
+}
+
+// IterateWikiPages returns an iterator over all wiki pages of a project. Every request fetches up to pageSize wiki
+// pages; values below 1 use Redmine's default page size.
+func (c *Client) IterateWikiPages(ctx context.Context, projectId int, pageSize int) *WikiPageIterator {
+	it := &WikiPageIterator{}
+	it.Paginator = newPaginator(ctx, pageSize, func(ctx context.Context, pagination ...string) (int, int, error) {
+		var r wikiPagesResult
+		err := c.get(ctx, c.pathWithParameters("/projects/"+strconv.Itoa(projectId)+"/wiki/index.json", pagination...), &r)
+		if err != nil {
+			return 0, 0, err
 		}
-	}
-	return r.WikiPages, nil
+		it.page = r.WikiPages
+		return len(r.WikiPages), r.TotalCount, nil
+	})
+	return it
 }
 
-// WikiPage fetches the wiki page with the given title.
-func (c *Client) WikiPage(projectId int, title string) (*WikiPage, error) {
-	return c.getWikiPage(projectId, title)
+// WikiPage fetches the wiki page with the given title. includes may contain WikiPageIncludeAttachments.
+func (c *Client) WikiPage(projectId int, title string, includes ...string) (*WikiPage, error) {
+	return c.WikiPageContext(context.Background(), projectId, title, includes...)
 }
 
-// WikiPageAtVersion fetches the wiki page with the given title at the given version.
-func (c *Client) WikiPageAtVersion(projectId int, title string, version string) (*WikiPage, error) {
-	return c.getWikiPage(projectId, title+"/"+version)
+// WikiPageContext is like WikiPage but binds the request to ctx.
+func (c *Client) WikiPageContext(ctx context.Context, projectId int, title string, includes ...string) (*WikiPage, error) {
+	return c.getWikiPage(ctx, projectId, title, includes)
 }
 
-func (c *Client) getWikiPage(projectId int, resource string) (*WikiPage, error) {
-	res, err := c.Get(c.endpoint + "/projects/" + strconv.Itoa(projectId) + "/wiki/" + resource + ".json?key=" + c.apikey)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
+// WikiPageAtVersion fetches the wiki page with the given title at the given version. includes may contain
+// WikiPageIncludeAttachments.
+func (c *Client) WikiPageAtVersion(projectId int, title string, version string, includes ...string) (*WikiPage, error) {
+	return c.WikiPageAtVersionContext(context.Background(), projectId, title, version, includes...)
+}
+
+// WikiPageAtVersionContext is like WikiPageAtVersion but binds the request to ctx.
+func (c *Client) WikiPageAtVersionContext(ctx context.Context, projectId int, title string, version string, includes ...string) (*WikiPage, error) {
+	return c.getWikiPage(ctx, projectId, title+"/"+version, includes)
+}
 
-	decoder := json.NewDecoder(res.Body)
+func (c *Client) getWikiPage(ctx context.Context, projectId int, resource string, includes []string) (*WikiPage, error) {
 	var r wikiPageResult
-	if res.StatusCode == 404 {
-		return nil, errors.New("Not Found")
-	}
-	if res.StatusCode != 200 {
-		var er errorsResult
-		if err = decoder.Decode(&er); err != nil {
-			return nil, err
-		}
-		return nil, errors.New(strings.Join(er.Errors, "\n"))
-	} else {
-		if err = decoder.Decode(&r); err != nil {
-			return nil, err
-		}
+	err := c.get(ctx, c.pathWithParameters("/projects/"+strconv.Itoa(projectId)+"/wiki/"+resource+".json", includeParameter(includes)), &r)
+	if err != nil {
+		return nil, err
 	}
 	return &r.WikiPage, nil
 }
 
 // CreateWikiPage creates wiki page.
 func (c *Client) CreateWikiPage(projectId int, wikiPage WikiPage) (*WikiPage, error) {
-	var wpr wikiPageRequest
-	wpr.WikiPage = wikiPage
-	s, err := json.Marshal(wpr)
-	if err != nil {
-		return nil, err
-	}
-	req, err := http.NewRequest("PUT", c.endpoint+"/projects/"+strconv.Itoa(projectId)+"/wiki/"+wikiPage.Title+".json?key="+c.apikey, strings.NewReader(string(s)))
-	if err != nil {
-		return nil, err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	res, err := c.Do(req)
-	if err != nil {
-		return nil, err
-	}
-	defer res.Body.Close()
+	return c.CreateWikiPageContext(context.Background(), projectId, wikiPage)
+}
 
-	decoder := json.NewDecoder(res.Body)
+// CreateWikiPageContext is like CreateWikiPage but binds the request to ctx.
+func (c *Client) CreateWikiPageContext(ctx context.Context, projectId int, wikiPage WikiPage) (*WikiPage, error) {
 	var r wikiPageResult
-	if res.StatusCode != 201 {
-		var er errorsResult
-		if err = decoder.Decode(&er); err != nil {
-			return nil, err
-		}
-		return nil, errors.New(strings.Join(er.Errors, "\n"))
-	} else {
-		if err := decoder.Decode(&r); err != nil {
-			return nil, err
-		}
+	err := c.put(ctx, "/projects/"+strconv.Itoa(projectId)+"/wiki/"+wikiPage.Title+".json", wikiPageRequest{WikiPage: wikiPage}, &r)
+	if err != nil {
+		return nil, err
 	}
 	return &r.WikiPage, nil
 }
 
 // UpdateWikiPage updates the wiki page given by the Title field of wikiPage.
 func (c *Client) UpdateWikiPage(projectId int, wikiPage WikiPage) error {
-	var wpr wikiPageRequest
-	wpr.WikiPage = wikiPage
-	s, err := json.Marshal(wpr)
-	if err != nil {
-		return err
-	}
-	req, err := http.NewRequest("PUT", c.endpoint+"/projects/"+strconv.Itoa(projectId)+"/wiki/"+wikiPage.Title+".json?key="+c.apikey, strings.NewReader(string(s)))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	res, err := c.Do(req)
-	if err != nil {
-		return err
-	}
-	defer res.Body.Close()
-	if res.StatusCode == 404 {
-		return errors.New("Not Found")
-	}
+	return c.UpdateWikiPageContext(context.Background(), projectId, wikiPage)
+}
 
-	if res.StatusCode != 200 {
-		decoder := json.NewDecoder(res.Body)
-		var er errorsResult
-		if err := decoder.Decode(&er); err != nil {
-			return err
-		}
-		return errors.New(strings.Join(er.Errors, "\n"))
-	}
-	return nil
+// UpdateWikiPageContext is like UpdateWikiPage but binds the request to ctx.
+func (c *Client) UpdateWikiPageContext(ctx context.Context, projectId int, wikiPage WikiPage) error {
+	return c.put(ctx, "/projects/"+strconv.Itoa(projectId)+"/wiki/"+wikiPage.Title+".json", wikiPageRequest{WikiPage: wikiPage}, nil)
 }
 
 // DeleteWikiPage deletes the wiki page given by its title irreversibly.
 func (c *Client) DeleteWikiPage(projectId int, title string) error {
-	req, err := http.NewRequest("DELETE", c.endpoint+"/projects/"+strconv.Itoa(projectId)+"/wiki/"+title+".json?key="+c.apikey, strings.NewReader(""))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json")
-	res, err := c.Do(req)
-	if err != nil {
-		return err
-	}
-	defer res.Body.Close()
-
-	if res.StatusCode == 404 {
-		return errors.New("Not Found")
-	}
+	return c.DeleteWikiPageContext(context.Background(), projectId, title)
+}
 
-	decoder := json.NewDecoder(res.Body)
-	if res.StatusCode != 200 {
-		var er errorsResult
-		if err := decoder.Decode(&er); err != nil {
-			return err
-		}
-		return errors.New(strings.Join(er.Errors, "\n"))
-	}
-	return nil
+// DeleteWikiPageContext is like DeleteWikiPage but binds the request to ctx.
+func (c *Client) DeleteWikiPageContext(ctx context.Context, projectId int, title string) error {
+	return c.delete(ctx, "/projects/"+strconv.Itoa(projectId)+"/wiki/"+title+".json")
 }
@@ -0,0 +1,27 @@
+package redmine
+
+import (
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestClient_WikiPage(t *testing.T) {
+	t.Run("should include attachments", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, "/projects/1/wiki/Start.json", r.URL.Path)
+			assert.Equal(t, "attachments", r.URL.Query().Get("include"))
+			_, _ = fmt.Fprintln(w, `{"wiki_page": {"title": "Start", "attachments": [{"id": 6, "filename": "screenshot.png"}]}}`)
+		}))
+		defer ts.Close()
+		sut := NewClient(ts.URL, WithAPIKey("apiKey"))
+
+		actual, err := sut.WikiPage(1, "Start", WikiPageIncludeAttachments)
+
+		require.NoError(t, err)
+		assert.Equal(t, []Attachment{{Id: 6, Filename: "screenshot.png"}}, actual.Attachments)
+	})
+}